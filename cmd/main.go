@@ -14,27 +14,29 @@ import (
 )
 
 func main() {
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
 	cfg := config.New()
 
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		code := runReconcile(ctx, cfg, os.Args[2:], os.Stdout, os.Stderr)
+		stop()
+		os.Exit(code) //nolint:gocritic // stop() is called explicitly above.
+	}
+
 	log, err := setupLogger(cfg)
 	if err != nil {
 		panic(fmt.Sprintf("failed to setup logger: %+v", err))
 	}
 
-	go func() {
-		if err = app.Run(ctx, cfg, log); err != nil {
-			log.Error("critical service error", "error", err)
-			stop()
-			return
-		}
-	}()
-
-	<-ctx.Done()
+	if err = app.Run(ctx, cfg, log); err != nil {
+		log.Error("critical service error", "error", err)
+		stop()
+		os.Exit(1) //nolint:gocritic // stop() is called explicitly above.
+	}
 
-	log.Info("shutdown service ...")
+	log.Info("service stopped")
 }
 
 func setupLogger(cfg *config.Config) (*slog.Logger, error) {
@@ -52,15 +54,20 @@ func setupLogger(cfg *config.Config) (*slog.Logger, error) {
 		return nil, fmt.Errorf("unknown log level: %s", cfg.LogLevel)
 	}
 
-	logger := slog.New(
-		slog.NewTextHandler(
-			os.Stdout,
-			&slog.HandlerOptions{
-				Level:     level,
-				AddSource: true,
-			},
-		),
-	)
+	opts := &slog.HandlerOptions{
+		Level:     level,
+		AddSource: true,
+	}
+
+	var handler slog.Handler
+	switch strings.ToLower(cfg.LogFormat) {
+	case "text":
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format: %s", cfg.LogFormat)
+	}
 
-	return logger, nil
+	return slog.New(handler), nil
 }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/derticom/merch-store/config"
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/repositories"
+	"github.com/derticom/merch-store/internal/services"
+)
+
+// Коды завершения команды reconcile.
+const (
+	exitOK    = 0
+	exitError = 1
+	// exitMismatches - найдены расхождения, и они не исправлены. Позволяет запускать сверку по расписанию
+	// и получать оповещение по коду завершения.
+	exitMismatches = 2
+)
+
+// runReconcile выполняет команду reconcile: пересчитывает балансы по истории начислений, переводов и покупок,
+// печатает расхождения и с флагом -fix записывает корректирующие проводки. Миграции не применяются,
+// схема БД должна быть актуальной.
+func runReconcile(ctx context.Context, cfg *config.Config, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "table", "формат отчета: table или json")
+	fix := fs.Bool("fix", false, "записать корректирующие проводки, приводящие балансы к ожидаемым")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(stderr, "unknown format: %s\n", *format)
+		return exitError
+	}
+
+	storage, err := repositories.New(ctx, cfg.PostgresURL)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to connect to postgres: %v\n", err)
+		return exitError
+	}
+	defer storage.Close()
+
+	return reconcile(ctx, services.New(storage), *format, *fix, stdout, stderr)
+}
+
+// reconciler пересчитывает балансы и при необходимости исправляет расхождения.
+type reconciler interface {
+	Reconcile(ctx context.Context, fix bool) (*models.ReconcileReport, error)
+}
+
+// reconcile выполняет сверку, печатает отчет в формате format и возвращает код завершения команды.
+func reconcile(ctx context.Context, r reconciler, format string, fix bool, stdout, stderr io.Writer) int {
+	report, err := r.Reconcile(ctx, fix)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to reconcile balances: %v\n", err)
+		return exitError
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = writeReconcileTable(stdout, report)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to write report: %v\n", err)
+		return exitError
+	}
+
+	if len(report.Mismatches) > 0 && !report.Fixed {
+		return exitMismatches
+	}
+
+	return exitOK
+}
+
+func writeReconcileTable(w io.Writer, report *models.ReconcileReport) error {
+	if len(report.Mismatches) > 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "USER\tGRANTS\tRECEIVED\tSENT\tPURCHASES\tEXPECTED\tACTUAL\tDIFF")
+		for _, m := range report.Mismatches {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%+d\n",
+				m.Username, m.Grants, m.Received, m.Sent, m.Purchases, m.Expected, m.Actual, m.Difference())
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+
+	summary := fmt.Sprintf("checked %d users, %d mismatches", report.Checked, len(report.Mismatches))
+	if report.Fixed && len(report.Mismatches) > 0 {
+		summary += ", corrective adjustments written"
+	}
+	_, err := fmt.Fprintln(w, summary)
+
+	return err
+}
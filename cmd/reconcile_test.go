@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/derticom/merch-store/config"
+	"github.com/derticom/merch-store/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubReconciler возвращает заранее заданный отчет и запоминает, запрошено ли исправление.
+type stubReconciler struct {
+	report *models.ReconcileReport
+	err    error
+	fix    bool
+}
+
+func (r *stubReconciler) Reconcile(_ context.Context, fix bool) (*models.ReconcileReport, error) {
+	r.fix = fix
+	return r.report, r.err
+}
+
+func TestRunReconcile_InvalidFlags(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedStderr string
+	}{
+		{
+			name:           "unknown flag",
+			args:           []string{"-dry-run"},
+			expectedStderr: "flag provided but not defined: -dry-run",
+		},
+		{
+			name:           "unknown format",
+			args:           []string{"-format", "csv"},
+			expectedStderr: "unknown format: csv\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			// Флаги проверяются до подключения к БД, поэтому конфигурация не нужна.
+			code := runReconcile(context.Background(), &config.Config{}, tt.args, &stdout, &stderr)
+
+			assert.Equal(t, exitError, code)
+			assert.Empty(t, stdout.String())
+			assert.Contains(t, stderr.String(), tt.expectedStderr)
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	mismatch := models.BalanceReconciliation{
+		UserID:    uuid.MustParse("6f1d2c3b-4a5e-4f60-8a7b-9c0d1e2f3a4b"),
+		Username:  "alice",
+		Grants:    1000,
+		Received:  50,
+		Sent:      20,
+		Purchases: 80,
+		Expected:  950,
+		Actual:    900,
+	}
+
+	tests := []struct {
+		name           string
+		reconciler     *stubReconciler
+		format         string
+		fix            bool
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{
+			name:           "consistent balances",
+			reconciler:     &stubReconciler{report: &models.ReconcileReport{Checked: 3}},
+			format:         "table",
+			expectedCode:   exitOK,
+			expectedStdout: "checked 3 users, 0 mismatches\n",
+		},
+		{
+			name: "mismatches are reported as a table",
+			reconciler: &stubReconciler{report: &models.ReconcileReport{
+				Checked:    3,
+				Mismatches: []models.BalanceReconciliation{mismatch},
+			}},
+			format:       "table",
+			expectedCode: exitMismatches,
+			expectedStdout: "USER   GRANTS  RECEIVED  SENT  PURCHASES  EXPECTED  ACTUAL  DIFF\n" +
+				"alice  1000    50        20    80         950       900     +50\n" +
+				"\n" +
+				"checked 3 users, 1 mismatches\n",
+		},
+		{
+			name: "fixed mismatches",
+			reconciler: &stubReconciler{report: &models.ReconcileReport{
+				Checked:    3,
+				Mismatches: []models.BalanceReconciliation{mismatch},
+				Fixed:      true,
+			}},
+			format:       "table",
+			fix:          true,
+			expectedCode: exitOK,
+			expectedStdout: "USER   GRANTS  RECEIVED  SENT  PURCHASES  EXPECTED  ACTUAL  DIFF\n" +
+				"alice  1000    50        20    80         950       900     +50\n" +
+				"\n" +
+				"checked 3 users, 1 mismatches, corrective adjustments written\n",
+		},
+		{
+			name: "json report",
+			reconciler: &stubReconciler{report: &models.ReconcileReport{
+				Checked:    1,
+				Mismatches: []models.BalanceReconciliation{mismatch},
+			}},
+			format:       "json",
+			expectedCode: exitMismatches,
+			expectedStdout: `{
+  "checked": 1,
+  "mismatches": [
+    {
+      "userId": "6f1d2c3b-4a5e-4f60-8a7b-9c0d1e2f3a4b",
+      "username": "alice",
+      "grants": 1000,
+      "received": 50,
+      "sent": 20,
+      "purchases": 80,
+      "expected": 950,
+      "actual": 900
+    }
+  ],
+  "fixed": false
+}
+`,
+		},
+		{
+			name:           "reconcile error",
+			reconciler:     &stubReconciler{err: errors.New("connection reset")},
+			format:         "json",
+			expectedCode:   exitError,
+			expectedStderr: "failed to reconcile balances: connection reset\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := reconcile(context.Background(), tt.reconciler, tt.format, tt.fix, &stdout, &stderr)
+
+			assert.Equal(t, tt.expectedCode, code)
+			assert.Equal(t, tt.fix, tt.reconciler.fix)
+			assert.Equal(t, tt.expectedStdout, stdout.String())
+			assert.Equal(t, tt.expectedStderr, stderr.String())
+		})
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -10,8 +11,91 @@ import (
 //nolint:tagliatelle // snake_case is allowed here.
 type Config struct {
 	LogLevel    string `yaml:"log_level"`
+	LogFormat   string `yaml:"log_format" env-default:"text"` // text или json.
 	PostgresURL string `yaml:"postgres_url"`
 	Port        string `yaml:"port"`
+	// ShutdownTimeout - время на завершение обрабатываемых запросов при остановке сервиса.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"10s"`
+	// IdempotencyKeyTTL - сколько хранится ответ на запрос с заголовком Idempotency-Key.
+	IdempotencyKeyTTL time.Duration `yaml:"idempotency_key_ttl" env-default:"24h"`
+	// RefundWindow - сколько времени после покупки можно запросить возврат.
+	RefundWindow time.Duration `yaml:"refund_window" env-default:"168h"`
+	Tracing      Tracing       `yaml:"tracing"`
+	Auth         Auth          `yaml:"auth"`
+	LegacyBuy    LegacyBuy     `yaml:"legacy_buy"`
+	Onboarding   Onboarding    `yaml:"onboarding"`
+}
+
+// Onboarding - монеты, которые получает новый пользователь при регистрации.
+//
+//nolint:tagliatelle // snake_case is allowed here.
+type Onboarding struct {
+	// InitialBalance - начальный баланс. Если не задан, пользователь получает 1000 монет; ноль допустим.
+	InitialBalance *int `yaml:"initial_balance"`
+	// WelcomeBonuses - бонусы сверх начального баланса. Пользователь получает все подходящие бонусы.
+	WelcomeBonuses []WelcomeBonus `yaml:"welcome_bonuses"`
+}
+
+//nolint:tagliatelle // snake_case is allowed here.
+type WelcomeBonus struct {
+	// Name - название правила, попадает в причину начисления в истории монет.
+	Name   string `yaml:"name"`
+	Amount int    `yaml:"amount"`
+	// From и Until ограничивают дату регистрации: бонус действует с From и до Until (не включая).
+	From  time.Time `yaml:"from"`
+	Until time.Time `yaml:"until"`
+	// InviteCode - если задан, бонус получают только пользователи, указавшие этот код при регистрации.
+	InviteCode string `yaml:"invite_code"`
+}
+
+// LegacyBuy - устаревший маршрут покупки GET /api/buy/{item}, оставленный на время перехода на POST /api/buy.
+type LegacyBuy struct {
+	// Enabled включает маршрут. Без него GET-запрос на покупку возвращает 404.
+	Enabled bool `yaml:"enabled"`
+	// Deprecation - дата, с которой маршрут считается устаревшим, сообщается клиентам в заголовке Deprecation.
+	Deprecation time.Time `yaml:"deprecation"`
+	// Sunset - дата отключения маршрута, сообщается клиентам в заголовке Sunset.
+	Sunset time.Time `yaml:"sunset"`
+}
+
+//nolint:tagliatelle // snake_case is allowed here.
+type Auth struct {
+	// AccessTokenTTL - время жизни access-токена. Отозвать его до истечения можно только через logout.
+	AccessTokenTTL time.Duration `yaml:"access_token_ttl" env-default:"15m"`
+	// RefreshTokenTTL - время жизни refresh-токена, по которому выдается новая пара токенов.
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env-default:"720h"`
+	// Secret - общий секрет для HS256. Используется, только если не заданы ключи Keys.
+	Secret string `yaml:"-" env:"JWT_SECRET"`
+	// SigningKeyID - kid ключа из Keys, которым подписываются новые токены.
+	SigningKeyID string `yaml:"signing_key_id"`
+	// Keys - ключи для подписи и проверки токенов. Ключ без приватной части только проверяет подпись,
+	// так при ротации старые токены остаются действительными до истечения срока.
+	Keys []JWTKey `yaml:"keys"`
+}
+
+//nolint:tagliatelle // snake_case is allowed here.
+type JWTKey struct {
+	ID string `yaml:"id"`
+	// Algorithm - RS256 или EdDSA.
+	Algorithm string `yaml:"algorithm"`
+	// PrivateKeyFile - PEM-файл с приватным ключом; нужен только ключу, которым подписываются токены.
+	PrivateKeyFile string `yaml:"private_key_file"`
+	// PublicKeyFile - PEM-файл с публичным ключом; можно не задавать, если задан приватный.
+	PublicKeyFile string `yaml:"public_key_file"`
+}
+
+//nolint:tagliatelle // snake_case is allowed here.
+type Tracing struct {
+	// Exporter - куда отправлять спаны: none, stdout или otlp.
+	Exporter string `yaml:"exporter" env-default:"none"`
+	// OTLPEndpoint - адрес OTLP/HTTP коллектора, например otel-collector:4318.
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+	// OTLPInsecure отключает TLS при отправке в коллектор.
+	OTLPInsecure bool `yaml:"otlp_insecure"`
+	// File - файл для stdout-экспортера; если не задан, спаны пишутся в stdout.
+	File string `yaml:"file"`
+	// SampleRatio - доля запросов, для которых записываются трейсы.
+	SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
 }
 
 func New() *Config {
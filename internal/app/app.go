@@ -2,36 +2,122 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
 	"github.com/derticom/merch-store/config"
 	"github.com/derticom/merch-store/internal/handlers"
+	"github.com/derticom/merch-store/internal/logging"
+	"github.com/derticom/merch-store/internal/metrics"
+	"github.com/derticom/merch-store/internal/models"
 	"github.com/derticom/merch-store/internal/repositories"
 	"github.com/derticom/merch-store/internal/server"
 	"github.com/derticom/merch-store/internal/services"
+	"github.com/derticom/merch-store/internal/tokens"
+	"github.com/derticom/merch-store/internal/tracing"
 
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 )
 
+// Run запускает сервис и блокируется до отмены ctx. При отмене сервер перестает принимать
+// соединения, дожидается завершения текущих запросов и только после этого закрывает пул соединений с БД.
 func Run(ctx context.Context, cfg *config.Config, log *slog.Logger) error {
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	defer func() {
+		// Контекст уже отменен, но накопленные спаны нужно успеть отправить.
+		flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cfg.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.Error("failed to shutdown tracing", "error", err)
+		}
+	}()
+
 	storage, err := repositories.New(ctx, cfg.PostgresURL)
 	if err != nil {
 		return fmt.Errorf("failed to connect to postgres: %w", err)
 	}
+	defer func() {
+		if err := storage.Close(); err != nil {
+			log.Error("failed to close storage", "error", err)
+		}
+	}()
 
 	if err := storage.Migrate("./migrations"); err != nil {
 		return fmt.Errorf("failed to apply migrations: %w", err)
 	}
 
-	service := services.New(storage)
+	if err := metrics.RegisterDBStats(storage); err != nil {
+		return fmt.Errorf("failed to register db metrics: %w", err)
+	}
+
+	onboarding, err := onboardingOptions(cfg.Onboarding)
+	if err != nil {
+		return fmt.Errorf("invalid onboarding config: %w", err)
+	}
+
+	service := services.New(storage, append([]services.Option{
+		services.WithRefreshTokenTTL(cfg.Auth.RefreshTokenTTL),
+		services.WithIdempotencyKeyTTL(cfg.IdempotencyKeyTTL),
+		services.WithRefundWindow(cfg.RefundWindow),
+	}, onboarding...)...)
+
+	tokenManager, err := tokens.New(cfg.Auth)
+	if err != nil {
+		return fmt.Errorf("failed to load token keys: %w", err)
+	}
+
+	var handlerOpts []handlers.Option
+	if cfg.LegacyBuy.Enabled {
+		handlerOpts = append(handlerOpts, handlers.WithLegacyBuyRoute(cfg.LegacyBuy.Deprecation, cfg.LegacyBuy.Sunset))
+	}
 
-	handler := handlers.New(service)
+	handler := handlers.New(service, storage, log, tokenManager, handlerOpts...)
 
 	router := mux.NewRouter()
+	router.Use(otelmux.Middleware(tracing.ServiceName), logging.Middleware(log), metrics.Middleware)
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
 	handler.RegisterRoutes(router)
 
-	srv := server.New(cfg.Port, router, log)
+	srv := server.New(cfg.Port, router, cfg.ShutdownTimeout, log)
+	srv.RegisterOnShutdown(handler.StartShutdown)
+
+	return srv.Run(ctx)
+}
+
+// onboardingOptions проверяет начальный баланс и правила приветственных бонусов из конфигурации.
+func onboardingOptions(cfg config.Onboarding) ([]services.Option, error) {
+	var opts []services.Option
+	if cfg.InitialBalance != nil {
+		if *cfg.InitialBalance < 0 {
+			return nil, errors.New("initial balance cannot be negative")
+		}
+		opts = append(opts, services.WithInitialBalance(*cfg.InitialBalance))
+	}
+
+	bonuses := make([]models.WelcomeBonus, 0, len(cfg.WelcomeBonuses))
+	for i, rule := range cfg.WelcomeBonuses {
+		switch {
+		case rule.Name == "":
+			return nil, fmt.Errorf("welcome bonus %d: name is required", i+1)
+		case rule.Amount <= 0:
+			return nil, fmt.Errorf("welcome bonus %q: amount must be positive", rule.Name)
+		case !rule.From.IsZero() && !rule.Until.IsZero() && !rule.Until.After(rule.From):
+			return nil, fmt.Errorf("welcome bonus %q: until must be after from", rule.Name)
+		}
+
+		bonuses = append(bonuses, models.WelcomeBonus{
+			Name:       rule.Name,
+			Amount:     rule.Amount,
+			From:       rule.From,
+			Until:      rule.Until,
+			InviteCode: rule.InviteCode,
+		})
+	}
 
-	return srv.Start()
+	return append(opts, services.WithWelcomeBonuses(bonuses...)), nil
 }
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/derticom/merch-store/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// SetUserRole - обработчик для назначения роли пользователю.
+func (h *Handler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	username := mux.Vars(r)["username"]
+
+	user, err := h.service.SetUserRole(r.Context(), username, req.Role)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+// CreateItem - обработчик для добавления товара в каталог.
+func (h *Handler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name  string `json:"name"`
+		Price int    `json:"price"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	item, err := h.service.CreateItem(r.Context(), req.Name, req.Price)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, item)
+}
+
+// UpdateItem - обработчик для изменения цены, названия и ограничения покупок товара.
+func (h *Handler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name         *string `json:"name"`
+		Price        *int    `json:"price"`
+		PerUserLimit *int    `json:"perUserLimit"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	item, err := h.service.UpdateItem(r.Context(), mux.Vars(r)["name"], models.ItemUpdate{
+		Name:         req.Name,
+		Price:        req.Price,
+		PerUserLimit: req.PerUserLimit,
+	})
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, item)
+}
+
+// RestockItem - обработчик для пополнения остатка товара.
+func (h *Handler) RestockItem(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Quantity int    `json:"quantity"`
+		Note     string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	adminID := r.Context().Value(userIDKey).(uuid.UUID)
+
+	item, err := h.service.RestockItem(r.Context(), adminID, mux.Vars(r)["name"], req.Quantity, req.Note)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, item)
+}
+
+// SetItemStock - обработчик для установки остатка товара. {"stock": null} снимает ограничение количества.
+func (h *Handler) SetItemStock(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		// Stock разбирается отдельно, чтобы отличить явный null от пропущенного поля.
+		Stock json.RawMessage `json:"stock"`
+		Note  string          `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Stock) == 0 {
+		writeError(w, http.StatusBadRequest, "stock is required")
+		return
+	}
+
+	var stock *int
+	if err := json.Unmarshal(req.Stock, &stock); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	adminID := r.Context().Value(userIDKey).(uuid.UUID)
+
+	item, err := h.service.SetItemStock(r.Context(), adminID, mux.Vars(r)["name"], stock, req.Note)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, item)
+}
+
+// GetStockMovements - обработчик для получения журнала движений остатка товара.
+func (h *Handler) GetStockMovements(w http.ResponseWriter, r *http.Request) {
+	movements, err := h.service.GetStockMovements(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if movements == nil {
+		movements = []models.StockMovement{}
+	}
+
+	writeJSON(w, http.StatusOK, movements)
+}
+
+// RetireItem - обработчик для снятия товара с продажи.
+func (h *Handler) RetireItem(w http.ResponseWriter, r *http.Request) {
+	item, err := h.service.RetireItem(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, item)
+}
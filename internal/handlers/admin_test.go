@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/handlers/mocks"
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ItemManagement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_handlers.NewMockService(ctrl)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/items", handler.CreateItem).Methods(http.MethodPost)
+	router.HandleFunc("/items/{name}", handler.UpdateItem).Methods(http.MethodPatch)
+	router.HandleFunc("/items/{name}", handler.RetireItem).Methods(http.MethodDelete)
+	router.HandleFunc("/items/{name}/restock", handler.RestockItem).Methods(http.MethodPost)
+	router.HandleFunc("/items/{name}/stock", handler.SetItemStock).Methods(http.MethodPut)
+
+	adminID := uuid.New()
+	stock := 15
+
+	price := 25
+	limit := 2
+
+	tests := []struct {
+		name           string
+		setup          func()
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "create item",
+			setup: func() {
+				mockService.EXPECT().CreateItem(gomock.Any(), "sticker", 5).Return(&models.Item{Name: "sticker", Price: 5}, nil)
+			},
+			method:         http.MethodPost,
+			path:           "/items",
+			body:           `{"name":"sticker","price":5}`,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"name":"sticker","price":5}` + "\n",
+		},
+		{
+			name: "create existing item",
+			setup: func() {
+				mockService.EXPECT().CreateItem(gomock.Any(), "cup", 20).Return(nil, services.ErrItemExists)
+			},
+			method:         http.MethodPost,
+			path:           "/items",
+			body:           `{"name":"cup","price":20}`,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"errors":"item already exists"}` + "\n",
+		},
+		{
+			name: "update price",
+			setup: func() {
+				mockService.EXPECT().UpdateItem(gomock.Any(), "cup", models.ItemUpdate{Price: &price}).Return(&models.Item{Name: "cup", Price: price}, nil)
+			},
+			method:         http.MethodPatch,
+			path:           "/items/cup",
+			body:           `{"price":25}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"cup","price":25}` + "\n",
+		},
+		{
+			name: "update per-user limit",
+			setup: func() {
+				mockService.EXPECT().UpdateItem(gomock.Any(), "cup", models.ItemUpdate{PerUserLimit: &limit}).
+					Return(&models.Item{Name: "cup", Price: 20, PerUserLimit: &limit}, nil)
+			},
+			method:         http.MethodPatch,
+			path:           "/items/cup",
+			body:           `{"perUserLimit":2}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"cup","price":20,"perUserLimit":2}` + "\n",
+		},
+		{
+			name: "restock item",
+			setup: func() {
+				mockService.EXPECT().RestockItem(gomock.Any(), adminID, "pink-hoody", 15, "october drop").
+					Return(&models.Item{Name: "pink-hoody", Price: 500, Stock: &stock}, nil)
+			},
+			method:         http.MethodPost,
+			path:           "/items/pink-hoody/restock",
+			body:           `{"quantity":15,"note":"october drop"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"pink-hoody","price":500,"stock":15}` + "\n",
+		},
+		{
+			name: "restock with zero quantity",
+			setup: func() {
+				mockService.EXPECT().RestockItem(gomock.Any(), adminID, "pink-hoody", 0, "").
+					Return(nil, services.ErrNonPositiveQuantity)
+			},
+			method:         http.MethodPost,
+			path:           "/items/pink-hoody/restock",
+			body:           `{"quantity":0}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"errors":"quantity must be positive"}` + "\n",
+		},
+		{
+			name: "set stock",
+			setup: func() {
+				mockService.EXPECT().SetItemStock(gomock.Any(), adminID, "pink-hoody", &stock, "inventory").
+					Return(&models.Item{Name: "pink-hoody", Price: 500, Stock: &stock}, nil)
+			},
+			method:         http.MethodPut,
+			path:           "/items/pink-hoody/stock",
+			body:           `{"stock":15,"note":"inventory"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"pink-hoody","price":500,"stock":15}` + "\n",
+		},
+		{
+			name: "make stock unlimited",
+			setup: func() {
+				mockService.EXPECT().SetItemStock(gomock.Any(), adminID, "pink-hoody", nil, "").
+					Return(&models.Item{Name: "pink-hoody", Price: 500}, nil)
+			},
+			method:         http.MethodPut,
+			path:           "/items/pink-hoody/stock",
+			body:           `{"stock":null}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"pink-hoody","price":500}` + "\n",
+		},
+		{
+			name:           "set stock without stock",
+			setup:          func() {},
+			method:         http.MethodPut,
+			path:           "/items/pink-hoody/stock",
+			body:           `{"note":"inventory"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"stock is required"}` + "\n",
+		},
+		{
+			name: "retire unknown item",
+			setup: func() {
+				mockService.EXPECT().RetireItem(gomock.Any(), "cap").Return(nil, services.ErrItemNotFound)
+			},
+			method:         http.MethodDelete,
+			path:           "/items/cap",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"errors":"item not found"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req, err := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			assert.NoError(t, err)
+			req = req.WithContext(context.WithValue(req.Context(), userIDKey, adminID))
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -2,42 +2,43 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
 	"time"
 
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
-const expDuration = 24 * time.Hour
+// tokenResponse - пара токенов, выдаваемая при входе и при обновлении.
+type tokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
 
 // Register - обработчик для регистрации.
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
+		Username   string `json:"username"`
+		Password   string `json:"password"`
+		InviteCode string `json:"inviteCode"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	user, err := h.service.RegisterUser(r.Context(), req.Username, req.Password)
+	user, err := h.service.RegisterUser(r.Context(), req.Username, req.Password, req.InviteCode)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		h.handleError(w, r, err)
 		return
 	}
 
-	token, err := generateJWT(user.ID)
-	if err != nil {
-		http.Error(w, "failed to generate token", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"token": token})
+	h.issueTokens(w, r, user)
 }
 
 // Login - обработчик для авторизации.
@@ -47,40 +48,107 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		Password string `json:"password"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	user, err := h.service.AuthenticateUser(r.Context(), req.Username, req.Password)
 	if err != nil {
-		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+		// Не раскрываем, что именно не так: имя пользователя или пароль.
+		if errors.Is(err, services.ErrNotFound) || errors.Is(err, services.ErrUnauthorized) {
+			writeError(w, http.StatusUnauthorized, "invalid credentials")
+			return
+		}
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.issueTokens(w, r, user)
+}
+
+// Refresh - обработчик для обмена refresh-токена на новую пару токенов.
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	token, err := generateJWT(user.ID)
+	if req.RefreshToken == "" {
+		writeError(w, http.StatusBadRequest, "refresh token is required")
+		return
+	}
+
+	user, refreshToken, err := h.service.RefreshSession(r.Context(), req.RefreshToken)
 	if err != nil {
-		http.Error(w, "failed to generate token", http.StatusInternalServerError)
+		h.handleError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"token": token})
+	h.writeTokens(w, r, user, refreshToken)
 }
 
-// generateJWT создает JWT-токен для пользователя.
-func generateJWT(userID uuid.UUID) (string, error) {
-	secretKey := os.Getenv("JWT_SECRET")
-	if secretKey == "" {
-		return "", fmt.Errorf("JWT secret key is not set")
+// Logout - обработчик для выхода: отзывает текущий access-токен и семейство переданного refresh-токена.
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	// Тело необязательно: без refresh-токена отзывается только access-токен.
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
 	}
 
-	claims := jwt.MapClaims{
-		"sub": userID.String(),
-		"exp": time.Now().Add(expDuration).Unix(),
-		"iat": time.Now().Unix(),
+	userID := r.Context().Value(userIDKey).(uuid.UUID)
+	jti := r.Context().Value(tokenIDKey).(uuid.UUID)
+	expiresAt := r.Context().Value(tokenExpiresAtKey).(time.Time)
+
+	if err := h.service.Logout(r.Context(), userID, req.RefreshToken, jti, expiresAt); err != nil {
+		h.handleError(w, r, err)
+		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	w.WriteHeader(http.StatusOK)
+}
+
+// issueTokens выдает пару токенов после входа.
+func (h *Handler) issueTokens(w http.ResponseWriter, r *http.Request, user *models.User) {
+	refreshToken, err := h.service.IssueRefreshToken(r.Context(), user.ID)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.writeTokens(w, r, user, refreshToken)
+}
+
+func (h *Handler) writeTokens(w http.ResponseWriter, r *http.Request, user *models.User, refreshToken string) {
+	token, err := h.generateJWT(user)
+	if err != nil {
+		h.handleError(w, r, fmt.Errorf("failed to generate token: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(tokenResponse{Token: token, RefreshToken: refreshToken})
+}
+
+// generateJWT создает access-токен для пользователя. jti позволяет отозвать токен до истечения срока,
+// а роль проверяется RequireRole без обращения к БД.
+func (h *Handler) generateJWT(user *models.User) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub":  user.ID.String(),
+		"role": user.Role,
+		"jti":  uuid.NewString(),
+		"exp":  now.Add(h.tokens.TTL()).Unix(),
+		"iat":  now.Unix(),
+	}
 
-	return token.SignedString([]byte(secretKey))
+	return h.tokens.Sign(claims)
 }
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derticom/merch-store/config"
+	"github.com/derticom/merch-store/internal/handlers/mocks"
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+	"github.com/derticom/merch-store/internal/tokens"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_handlers.NewMockService(ctrl)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), newTestTokens(t))
+
+	user := &models.User{ID: uuid.New(), Role: models.RoleEmployee}
+
+	tests := []struct {
+		name           string
+		setup          func()
+		requestBody    map[string]interface{}
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "successful refresh",
+			setup: func() {
+				mockService.EXPECT().RefreshSession(gomock.Any(), "old").Return(user, "new", nil)
+			},
+			requestBody:    map[string]interface{}{"refreshToken": "old"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing refresh token",
+			setup:          func() {},
+			requestBody:    map[string]interface{}{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"refresh token is required"}` + "\n",
+		},
+		{
+			name: "reused refresh token",
+			setup: func() {
+				mockService.EXPECT().RefreshSession(gomock.Any(), "old").Return(nil, "", services.ErrRefreshTokenReused)
+			},
+			requestBody:    map[string]interface{}{"refreshToken": "old"},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"errors":"refresh token reuse detected"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			reqBody, _ := json.Marshal(tt.requestBody)
+			req, err := http.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewBuffer(reqBody))
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/auth/refresh", handler.Refresh).Methods(http.MethodPost)
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, tt.expectedBody, rr.Body.String())
+				return
+			}
+
+			var resp tokenResponse
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+			assert.NotEmpty(t, resp.Token)
+			assert.Equal(t, "new", resp.RefreshToken)
+		})
+	}
+}
+
+func TestHandler_JWTAuthMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_handlers.NewMockService(ctrl)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), newTestTokens(t))
+
+	user := &models.User{ID: uuid.New(), Role: models.RoleEmployee}
+	token, err := handler.generateJWT(user)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		setup          func()
+		authHeader     string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "valid token",
+			setup: func() {
+				mockService.EXPECT().IsAccessTokenRevoked(gomock.Any(), gomock.Any()).Return(false, nil)
+			},
+			authHeader:     "Bearer " + token,
+			expectedStatus: http.StatusOK,
+			expectedBody:   user.ID.String(),
+		},
+		{
+			name: "revoked token",
+			setup: func() {
+				mockService.EXPECT().IsAccessTokenRevoked(gomock.Any(), gomock.Any()).Return(true, nil)
+			},
+			authHeader:     "Bearer " + token,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"errors":"token revoked"}` + "\n",
+		},
+		{
+			name:           "missing token",
+			setup:          func() {},
+			authHeader:     "",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"errors":"missing token"}` + "\n",
+		},
+		{
+			name:           "invalid signature",
+			setup:          func() {},
+			authHeader:     "Bearer " + token + "x",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"errors":"invalid token"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/info", nil)
+			assert.NoError(t, err)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+
+			rr := httptest.NewRecorder()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(r.Context().Value(userIDKey).(uuid.UUID).String()))
+			})
+			handler.JWTAuthMiddleware(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestRegisterRoutes_AdminRequiresRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_handlers.NewMockService(ctrl)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), newTestTokens(t))
+
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		name           string
+		role           string
+		setup          func()
+		expectedStatus int
+	}{
+		{
+			name: "admin",
+			role: models.RoleAdmin,
+			setup: func() {
+				mockService.EXPECT().SetUserRole(gomock.Any(), "bob", models.RoleAuditor).
+					Return(&models.User{Username: "bob", Role: models.RoleAuditor}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "employee",
+			role:           models.RoleEmployee,
+			setup:          func() {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "auditor",
+			role:           models.RoleAuditor,
+			setup:          func() {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			mockService.EXPECT().IsAccessTokenRevoked(gomock.Any(), gomock.Any()).Return(false, nil)
+
+			token, err := handler.generateJWT(&models.User{ID: uuid.New(), Role: tt.role})
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPut, "/api/admin/users/bob/role", bytes.NewBufferString(`{"role":"auditor"}`))
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
+
+func newTestTokens(t *testing.T) *tokens.Manager {
+	t.Helper()
+
+	manager, err := tokens.New(config.Auth{Secret: "test-secret"})
+	require.NoError(t, err)
+
+	return manager
+}
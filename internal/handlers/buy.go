@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
+	"github.com/derticom/merch-store/internal/logging"
+
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
 
-// BuyItem - обработчик для покупки товара.
+// BuyItem - обработчик для покупки товара через устаревший маршрут GET /api/buy/{item}.
 func (h *Handler) BuyItem(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	itemName := vars["item"]
@@ -15,9 +19,69 @@ func (h *Handler) BuyItem(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value(userIDKey).(uuid.UUID)
 
 	if err := h.service.BuyItem(r.Context(), userID, itemName); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		h.handleError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// deprecatedBuy помечает ответы устаревшего маршрута покупки заголовками Deprecation (RFC 9745) и Sunset
+// (RFC 8594), указывает на замену и пишет в лог каждое обращение, чтобы найти клиентов, которые еще его используют.
+func (h *Handler) deprecatedBuy(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !h.legacyBuyDeprecation.IsZero() {
+			w.Header().Set("Deprecation", fmt.Sprintf("@%d", h.legacyBuyDeprecation.Unix()))
+		}
+		if !h.legacyBuySunset.IsZero() {
+			w.Header().Set("Sunset", h.legacyBuySunset.UTC().Format(http.TimeFormat))
+		}
+		w.Header().Set("Link", `</api/buy>; rel="successor-version"`)
+
+		logging.FromContext(r.Context(), h.log).WarnContext(r.Context(), "deprecated endpoint used",
+			"path", r.URL.Path,
+			"user_id", r.Context().Value(userIDKey),
+			"user_agent", r.UserAgent(),
+		)
+
+		next(w, r)
+	}
+}
+
+// orderLineRequest - товар и количество в теле запроса; без quantity покупается одна единица.
+type orderLineRequest struct {
+	Item     string `json:"item"`
+	Quantity *int   `json:"quantity"`
+}
+
+func (req orderLineRequest) quantity() int {
+	if req.Quantity == nil {
+		return 1
+	}
+	return *req.Quantity
+}
+
+// BuyItems - обработчик для покупки товара: POST /api/buy с телом {"item": "...", "quantity": n}.
+// В ответе возвращаются созданные покупки и баланс после списания.
+func (h *Handler) BuyItems(w http.ResponseWriter, r *http.Request) {
+	var req orderLineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.Item == "" {
+		writeError(w, http.StatusBadRequest, "item is required")
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(uuid.UUID)
+
+	order, err := h.service.BuyItems(r.Context(), userID, req.Item, req.quantity())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, order)
+}
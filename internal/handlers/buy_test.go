@@ -3,16 +3,22 @@ package handlers
 import (
 	"context"
 	"errors"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/derticom/merch-store/internal/handlers/mocks"
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHandler_BuyItem(t *testing.T) {
@@ -20,7 +26,7 @@ func TestHandler_BuyItem(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock_handlers.NewMockService(ctrl)
-	handler := New(mockService)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
 
 	userID := uuid.New()
 	itemName := "umbrella"
@@ -46,32 +52,62 @@ func TestHandler_BuyItem(t *testing.T) {
 		{
 			name: "insufficient coins",
 			setup: func() {
-				mockService.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(errors.New("insufficient coins"))
+				mockService.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(services.ErrInsufficientCoins)
 			},
 			itemName:       itemName,
 			userID:         userID,
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "insufficient coins\n",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"errors":"insufficient coins"}` + "\n",
 		},
 		{
 			name: "item not found",
 			setup: func() {
-				mockService.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(errors.New("item not found"))
+				mockService.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(services.ErrItemNotFound)
 			},
 			itemName:       itemName,
 			userID:         userID,
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "item not found\n",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"errors":"item not found"}` + "\n",
+		},
+		{
+			name: "retired item",
+			setup: func() {
+				mockService.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(services.ErrItemRetired)
+			},
+			itemName:       itemName,
+			userID:         userID,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"errors":"item is no longer available"}` + "\n",
+		},
+		{
+			name: "out of stock",
+			setup: func() {
+				mockService.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(services.ErrOutOfStock)
+			},
+			itemName:       itemName,
+			userID:         userID,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"errors":"item is out of stock"}` + "\n",
 		},
 		{
 			name: "user not found",
 			setup: func() {
-				mockService.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(errors.New("user not found"))
+				mockService.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(services.ErrUserNotFound)
 			},
 			itemName:       itemName,
 			userID:         userID,
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "user not found\n",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"errors":"user not found"}` + "\n",
+		},
+		{
+			name: "internal error is not exposed",
+			setup: func() {
+				mockService.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(errors.New("pq: connection refused"))
+			},
+			itemName:       itemName,
+			userID:         userID,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"errors":"internal server error"}` + "\n",
 		},
 	}
 
@@ -97,3 +133,64 @@ func TestHandler_BuyItem(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterRoutes_LegacyBuy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_handlers.NewMockService(ctrl)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	deprecation := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		opts           []Option
+		setup          func()
+		expectedStatus int
+		expectedSunset string
+	}{
+		{
+			name: "enabled route is marked deprecated",
+			opts: []Option{WithLegacyBuyRoute(deprecation, sunset)},
+			setup: func() {
+				mockService.EXPECT().BuyItem(gomock.Any(), userID, "cup").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedSunset: "Fri, 01 Jan 2027 00:00:00 GMT",
+		},
+		{
+			name:           "disabled route is not registered",
+			setup:          func() {},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			mockService.EXPECT().IsAccessTokenRevoked(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+
+			handler := New(mockService, nil, log, newTestTokens(t), tt.opts...)
+			router := mux.NewRouter()
+			handler.RegisterRoutes(router)
+
+			token, err := handler.generateJWT(&models.User{ID: userID, Role: models.RoleEmployee})
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodGet, "/api/buy/cup", nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedSunset, rr.Header().Get("Sunset"))
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "@1792281600", rr.Header().Get("Deprecation"))
+			}
+		})
+	}
+}
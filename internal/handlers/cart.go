@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetCart - обработчик для просмотра корзины.
+func (h *Handler) GetCart(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(uuid.UUID)
+
+	cart, err := h.service.GetCart(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cart)
+}
+
+// AddToCart - обработчик для добавления товара в корзину.
+func (h *Handler) AddToCart(w http.ResponseWriter, r *http.Request) {
+	var req orderLineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.Item == "" {
+		writeError(w, http.StatusBadRequest, "item is required")
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(uuid.UUID)
+
+	cart, err := h.service.AddToCart(r.Context(), userID, req.Item, req.quantity())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cart)
+}
+
+// RemoveFromCart - обработчик для удаления товара из корзины.
+func (h *Handler) RemoveFromCart(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(uuid.UUID)
+
+	cart, err := h.service.RemoveFromCart(r.Context(), userID, mux.Vars(r)["item"])
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cart)
+}
+
+// Checkout - обработчик для оформления заказа из корзины.
+func (h *Handler) Checkout(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(uuid.UUID)
+
+	order, err := h.service.Checkout(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, order)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/handlers/mocks"
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_BuyItemsAndCart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_handlers.NewMockService(ctrl)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/buy", handler.BuyItems).Methods(http.MethodPost)
+	router.HandleFunc("/cart/items", handler.AddToCart).Methods(http.MethodPost)
+	router.HandleFunc("/cart/items/{item}", handler.RemoveFromCart).Methods(http.MethodDelete)
+	router.HandleFunc("/cart/checkout", handler.Checkout).Methods(http.MethodPost)
+
+	userID := uuid.New()
+	order := &models.Order{
+		Purchases: []models.Purchase{{Item: "cup", Price: 20}, {Item: "cup", Price: 20}},
+		Total:     40,
+	}
+
+	tests := []struct {
+		name           string
+		setup          func()
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "buy several units",
+			setup: func() {
+				mockService.EXPECT().BuyItems(gomock.Any(), userID, "cup", 2).Return(order, nil)
+			},
+			method:         http.MethodPost,
+			path:           "/buy",
+			body:           `{"item":"cup","quantity":2}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "quantity defaults to one",
+			setup: func() {
+				mockService.EXPECT().BuyItems(gomock.Any(), userID, "cup", 1).Return(order, nil)
+			},
+			method:         http.MethodPost,
+			path:           "/buy",
+			body:           `{"item":"cup"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing item",
+			setup:          func() {},
+			method:         http.MethodPost,
+			path:           "/buy",
+			body:           `{"quantity":2}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"item is required"}` + "\n",
+		},
+		{
+			name: "add to cart",
+			setup: func() {
+				mockService.EXPECT().AddToCart(gomock.Any(), userID, "pen", 3).Return(&models.Cart{}, nil)
+			},
+			method:         http.MethodPost,
+			path:           "/cart/items",
+			body:           `{"item":"pen","quantity":3}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "remove missing cart item",
+			setup: func() {
+				mockService.EXPECT().RemoveFromCart(gomock.Any(), userID, "pen").Return(nil, services.ErrCartItemNotFound)
+			},
+			method:         http.MethodDelete,
+			path:           "/cart/items/pen",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"errors":"item is not in the cart"}` + "\n",
+		},
+		{
+			name: "checkout with insufficient coins",
+			setup: func() {
+				mockService.EXPECT().Checkout(gomock.Any(), userID).Return(nil, services.ErrInsufficientCoins)
+			},
+			method:         http.MethodPost,
+			path:           "/cart/checkout",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"errors":"insufficient coins"}` + "\n",
+		},
+		{
+			name: "checkout empty cart",
+			setup: func() {
+				mockService.EXPECT().Checkout(gomock.Any(), userID).Return(nil, services.ErrEmptyCart)
+			},
+			method:         http.MethodPost,
+			path:           "/cart/checkout",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"errors":"cart is empty"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req, err := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			assert.NoError(t, err)
+			req = req.WithContext(context.WithValue(req.Context(), userIDKey, userID))
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/derticom/merch-store/internal/logging"
+	"github.com/derticom/merch-store/internal/services"
+)
+
+// errorResponse - единый формат ответа с ошибкой.
+type errorResponse struct {
+	Errors string `json:"errors"`
+}
+
+// writeError отправляет клиенту ошибку в формате {"errors": "..."}.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Errors: message})
+}
+
+// handleError сопоставляет ошибку сервиса с HTTP-статусом. Внутренние ошибки логируются,
+// а клиент получает обобщенное сообщение без подробностей.
+func (h *Handler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrConflict):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInsufficientFunds), errors.Is(err, services.ErrValidation):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, services.ErrUnauthorized):
+		writeError(w, http.StatusUnauthorized, err.Error())
+	default:
+		logging.FromContext(r.Context(), h.log).ErrorContext(r.Context(), "internal error",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"error", err,
+		)
+		writeError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/derticom/merch-store/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GrantUserCoins - обработчик для начисления или списания монет одному пользователю:
+// POST /api/admin/users/{username}/coins с телом {"amount": n, "reason": "..."}.
+func (h *Handler) GrantUserCoins(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Amount int    `json:"amount"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	h.grantCoins(w, r, []models.CoinGrant{{
+		Username: mux.Vars(r)["username"],
+		Amount:   req.Amount,
+		Reason:   req.Reason,
+	}})
+}
+
+// GrantCoinsBatch - обработчик для пакетного начисления монет. Принимает JSON
+// {"reason": "...", "grants": [{"username": "...", "amount": n, "reason": "..."}]} или CSV (Content-Type: text/csv)
+// с колонками username, amount и необязательной reason; общая причина для CSV передается параметром ?reason=.
+// Причина строки имеет приоритет над общей.
+func (h *Handler) GrantCoinsBatch(w http.ResponseWriter, r *http.Request) {
+	var grants []models.CoinGrant
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		var err error
+		grants, err = parseCoinGrantsCSV(r.Body, r.URL.Query().Get("reason"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	} else {
+		var req struct {
+			Reason string             `json:"reason"`
+			Grants []models.CoinGrant `json:"grants"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		for i := range req.Grants {
+			if req.Grants[i].Reason == "" {
+				req.Grants[i].Reason = req.Reason
+			}
+		}
+		grants = req.Grants
+	}
+
+	h.grantCoins(w, r, grants)
+}
+
+func (h *Handler) grantCoins(w http.ResponseWriter, r *http.Request, grants []models.CoinGrant) {
+	adminID := r.Context().Value(userIDKey).(uuid.UUID)
+
+	batch, err := h.service.GrantCoins(r.Context(), adminID, grants)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, batch)
+}
+
+// parseCoinGrantsCSV читает начисления из CSV. Первая строка - заголовок с колонками username и amount,
+// колонка reason необязательна; пустая причина заменяется на defaultReason.
+func parseCoinGrantsCSV(body io.Reader, defaultReason string) ([]models.CoinGrant, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv header is required")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	usernameCol, hasUsername := columns["username"]
+	amountCol, hasAmount := columns["amount"]
+	if !hasUsername || !hasAmount {
+		return nil, errors.New(`csv must have "username" and "amount" columns`)
+	}
+	reasonCol, hasReason := columns["reason"]
+
+	var grants []models.CoinGrant
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		amount, err := strconv.Atoi(strings.TrimSpace(record[amountCol]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount", line)
+		}
+
+		grant := models.CoinGrant{
+			Username: record[usernameCol],
+			Amount:   amount,
+			Reason:   defaultReason,
+		}
+		if hasReason && strings.TrimSpace(record[reasonCol]) != "" {
+			grant.Reason = record[reasonCol]
+		}
+		grants = append(grants, grant)
+	}
+
+	return grants, nil
+}
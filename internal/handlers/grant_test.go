@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/handlers/mocks"
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_GrantCoins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_handlers.NewMockService(ctrl)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/users/{username}/coins", handler.GrantUserCoins).Methods(http.MethodPost)
+	router.HandleFunc("/admin/coins/grants", handler.GrantCoinsBatch).Methods(http.MethodPost)
+
+	adminID := uuid.New()
+	batchID := uuid.MustParse("0b6c8f1e-2d4a-4c3b-9e7f-5a1d2c3b4e5f")
+	batch := func(results ...models.CoinGrantResult) *models.CoinGrantBatch {
+		return &models.CoinGrantBatch{ID: batchID, Grants: results}
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		contentType    string
+		body           string
+		setup          func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "single grant",
+			path: "/admin/users/alice/coins",
+			body: `{"amount": 500, "reason": "hackathon winner"}`,
+			setup: func() {
+				mockService.EXPECT().GrantCoins(gomock.Any(), adminID, []models.CoinGrant{
+					{Username: "alice", Amount: 500, Reason: "hackathon winner"},
+				}).Return(batch(models.CoinGrantResult{
+					Username: "alice", Amount: 500, Reason: "hackathon winner", Balance: 1500,
+				}), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":"0b6c8f1e-2d4a-4c3b-9e7f-5a1d2c3b4e5f","grants":[` +
+				`{"username":"alice","amount":500,"reason":"hackathon winner","balance":1500}]}` + "\n",
+		},
+		{
+			name:           "single grant with invalid body",
+			path:           "/admin/users/alice/coins",
+			body:           `{"amount": "many"}`,
+			setup:          func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"invalid request body"}` + "\n",
+		},
+		{
+			name: "single grant without reason",
+			path: "/admin/users/alice/coins",
+			body: `{"amount": 500}`,
+			setup: func() {
+				mockService.EXPECT().GrantCoins(gomock.Any(), adminID, gomock.Any()).Return(nil, services.ErrReasonRequired)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"errors":"reason is required"}` + "\n",
+		},
+		{
+			name: "json batch uses common reason",
+			path: "/admin/coins/grants",
+			body: `{"reason": "Q3 bonus", "grants": [{"username": "alice", "amount": 100},` +
+				` {"username": "bob", "amount": -50, "reason": "overpayment"}]}`,
+			setup: func() {
+				mockService.EXPECT().GrantCoins(gomock.Any(), adminID, []models.CoinGrant{
+					{Username: "alice", Amount: 100, Reason: "Q3 bonus"},
+					{Username: "bob", Amount: -50, Reason: "overpayment"},
+				}).Return(batch(), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":"0b6c8f1e-2d4a-4c3b-9e7f-5a1d2c3b4e5f","grants":null}` + "\n",
+		},
+		{
+			name:        "csv batch",
+			path:        "/admin/coins/grants?reason=Q3+bonus",
+			contentType: "text/csv; charset=utf-8",
+			body:        "username,amount,reason\nalice,100,\nbob, -50, overpayment\n",
+			setup: func() {
+				mockService.EXPECT().GrantCoins(gomock.Any(), adminID, []models.CoinGrant{
+					{Username: "alice", Amount: 100, Reason: "Q3 bonus"},
+					{Username: "bob", Amount: -50, Reason: "overpayment"},
+				}).Return(batch(), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":"0b6c8f1e-2d4a-4c3b-9e7f-5a1d2c3b4e5f","grants":null}` + "\n",
+		},
+		{
+			name:           "csv without amount column",
+			path:           "/admin/coins/grants",
+			contentType:    "text/csv",
+			body:           "username,reason\nalice,award\n",
+			setup:          func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"csv must have \"username\" and \"amount\" columns"}` + "\n",
+		},
+		{
+			name:           "csv with invalid amount",
+			path:           "/admin/coins/grants",
+			contentType:    "text/csv",
+			body:           "username,amount\nalice,100\nbob,ten\n",
+			setup:          func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"line 3: invalid amount"}` + "\n",
+		},
+		{
+			name: "batch rejected by service",
+			path: "/admin/coins/grants",
+			body: `{"reason": "award", "grants": [{"username": "alice", "amount": 10}, {"username": "carol", "amount": 10}]}`,
+			setup: func() {
+				mockService.EXPECT().GrantCoins(gomock.Any(), adminID, gomock.Any()).
+					Return(nil, services.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"errors":"user not found"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req, err := http.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			req = req.WithContext(context.WithValue(req.Context(), userIDKey, adminID))
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
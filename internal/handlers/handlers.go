@@ -2,30 +2,110 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tokens"
 
 	"github.com/google/uuid"
 )
 
 //go:generate go run github.com/golang/mock/mockgen  -destination=mocks/mock_service.go . Service
 type Service interface {
-	RegisterUser(ctx context.Context, username, password string) (*models.User, error)
+	RegisterUser(ctx context.Context, username, password, inviteCode string) (*models.User, error)
 	AuthenticateUser(ctx context.Context, username, password string) (*models.User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 	UpdateUserCoins(ctx context.Context, id uuid.UUID, coins int) error
+	SetUserRole(ctx context.Context, username, role string) (*models.User, error)
 	GetAllItems(ctx context.Context) ([]models.Item, error)
 	GetItemByName(ctx context.Context, name string) (*models.Item, error)
+	ListItems(ctx context.Context, filter models.ItemFilter, cursor string) (*models.ItemPage, error)
+	CreateItem(ctx context.Context, name string, price int) (*models.Item, error)
+	UpdateItem(ctx context.Context, name string, update models.ItemUpdate) (*models.Item, error)
+	RestockItem(ctx context.Context, adminID uuid.UUID, name string, quantity int, note string) (*models.Item, error)
+	SetItemStock(ctx context.Context, adminID uuid.UUID, name string, stock *int, note string) (*models.Item, error)
+	GetStockMovements(ctx context.Context, name string) ([]models.StockMovement, error)
+	RetireItem(ctx context.Context, name string) (*models.Item, error)
 	BuyItem(ctx context.Context, userID uuid.UUID, itemName string) error
+	BuyItems(ctx context.Context, userID uuid.UUID, itemName string, quantity int) (*models.Order, error)
+	GetCart(ctx context.Context, userID uuid.UUID) (*models.Cart, error)
+	AddToCart(ctx context.Context, userID uuid.UUID, itemName string, quantity int) (*models.Cart, error)
+	RemoveFromCart(ctx context.Context, userID uuid.UUID, itemName string) (*models.Cart, error)
+	Checkout(ctx context.Context, userID uuid.UUID) (*models.Order, error)
 	GetPurchaseHistory(ctx context.Context, userID uuid.UUID) ([]models.Purchase, error)
-	SendCoins(ctx context.Context, fromUserID, toUserID uuid.UUID, amount int) error
+	RequestRefund(ctx context.Context, userID, purchaseID uuid.UUID) (*models.Purchase, error)
+	ApproveRefund(ctx context.Context, adminID, purchaseID uuid.UUID) (*models.Purchase, error)
+	GetRefundRequests(ctx context.Context) ([]models.Purchase, error)
+	AdvanceOrder(ctx context.Context, actorID, orderID uuid.UUID, to string) (*models.FulfillmentOrder, error)
+	ListOrders(ctx context.Context, status string) ([]models.FulfillmentOrder, error)
+	CheckLedger(ctx context.Context) (*models.LedgerCheck, error)
+	GrantCoins(ctx context.Context, adminID uuid.UUID, grants []models.CoinGrant) (*models.CoinGrantBatch, error)
+	GetInventory(ctx context.Context, userID uuid.UUID) ([]models.InventoryItem, error)
+	SendCoins(ctx context.Context, fromUserID uuid.UUID, toUser string, amount int) error
 	GetTransactionHistory(ctx context.Context, userID uuid.UUID) ([]models.Transaction, error)
+	GetCoinHistory(ctx context.Context, userID uuid.UUID) (*models.CoinHistory, error)
+	IssueRefreshToken(ctx context.Context, userID uuid.UUID) (string, error)
+	RefreshSession(ctx context.Context, refreshToken string) (*models.User, string, error)
+	Logout(ctx context.Context, userID uuid.UUID, refreshToken string, jti uuid.UUID, accessExpiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti uuid.UUID) (bool, error)
+	ExecuteIdempotentRequest(
+		ctx context.Context,
+		userID uuid.UUID,
+		key, requestHash string,
+		run func(ctx context.Context) *models.IdempotentResponse,
+	) (*models.IdempotentResponse, bool, error)
 }
 
 type Handler struct {
 	service Service
+	health  HealthChecker
+	log     *slog.Logger
+	tokens  *tokens.Manager
+
+	// legacyBuy включает устаревший маршрут GET /api/buy/{item}, legacyBuyDeprecation - дата, с которой
+	// он устарел, legacyBuySunset - дата его отключения.
+	legacyBuy            bool
+	legacyBuyDeprecation time.Time
+	legacyBuySunset      time.Time
+
+	// shuttingDown выставляется при начале остановки сервиса, после чего /readyz отвечает ошибкой.
+	shuttingDown atomic.Bool
+}
+
+// Option задает необязательные параметры Handler.
+type Option func(h *Handler)
+
+// WithLegacyBuyRoute регистрирует устаревший маршрут GET /api/buy/{item}. Нулевые даты не передаются клиентам.
+func WithLegacyBuyRoute(deprecation, sunset time.Time) Option {
+	return func(h *Handler) {
+		h.legacyBuy = true
+		h.legacyBuyDeprecation = deprecation
+		h.legacyBuySunset = sunset
+	}
+}
+
+func New(
+	service Service,
+	health HealthChecker,
+	log *slog.Logger,
+	tokenManager *tokens.Manager,
+	opts ...Option,
+) *Handler {
+	h := &Handler{service: service, health: health, log: log, tokens: tokenManager}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
-func New(service Service) *Handler {
-	return &Handler{service: service}
+// writeJSON отправляет клиенту ответ в формате JSON.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
 }
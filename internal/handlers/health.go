@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/derticom/merch-store/internal/logging"
+)
+
+const readinessTimeout = 2 * time.Second
+
+const (
+	statusOK   = "ok"
+	statusFail = "fail"
+)
+
+var errShuttingDown = errors.New("service is shutting down")
+
+//go:generate go run github.com/golang/mock/mockgen  -destination=mocks/mock_health.go . HealthChecker
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+	CheckMigrations(ctx context.Context) error
+}
+
+type checkResult struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+type healthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks,omitempty"`
+}
+
+// StartShutdown переводит сервис в состояние остановки: с этого момента /readyz отвечает ошибкой.
+func (h *Handler) StartShutdown() {
+	h.shuttingDown.Store(true)
+}
+
+// Healthz - обработчик проверки того, что процесс запущен.
+func (h *Handler) Healthz(w http.ResponseWriter, _ *http.Request) {
+	writeHealth(w, http.StatusOK, healthResponse{Status: statusOK})
+}
+
+// Readyz - обработчик проверки готовности сервиса принимать запросы.
+func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	// Эндпоинт доступен без аутентификации, поэтому в ответ попадает только message,
+	// а текст ошибки пишется в лог.
+	checks := []struct {
+		name    string
+		message string
+		check   func(ctx context.Context) error
+	}{
+		{name: "shutdown", message: errShuttingDown.Error(), check: func(context.Context) error {
+			if h.shuttingDown.Load() {
+				return errShuttingDown
+			}
+			return nil
+		}},
+		{name: "database", message: "database unavailable", check: h.health.Ping},
+		{name: "migrations", message: "database schema is not up to date", check: h.health.CheckMigrations},
+	}
+
+	response := healthResponse{
+		Status: statusOK,
+		Checks: make(map[string]checkResult, len(checks)),
+	}
+	status := http.StatusOK
+
+	for _, c := range checks {
+		start := time.Now()
+		err := c.check(ctx)
+		result := checkResult{
+			Status:  statusOK,
+			Latency: time.Since(start).String(),
+		}
+		if err != nil {
+			logging.FromContext(r.Context(), h.log).WarnContext(r.Context(), "readiness check failed",
+				"check", c.name,
+				"error", err,
+			)
+			result.Status = statusFail
+			result.Error = c.message
+			response.Status = statusFail
+			status = http.StatusServiceUnavailable
+		}
+		response.Checks[c.name] = result
+	}
+
+	writeHealth(w, status, response)
+}
+
+func writeHealth(w http.ResponseWriter, status int, response healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
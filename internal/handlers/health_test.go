@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/handlers/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Healthz(t *testing.T) {
+	handler := New(nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+}
+
+func TestHandler_Readyz(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name           string
+		setup          func(health *mock_handlers.MockHealthChecker)
+		shuttingDown   bool
+		expectedStatus int
+		expectedChecks map[string]string
+		expectedErrors map[string]string
+	}{
+		{
+			name: "ready",
+			setup: func(health *mock_handlers.MockHealthChecker) {
+				health.EXPECT().Ping(gomock.Any()).Return(nil)
+				health.EXPECT().CheckMigrations(gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedChecks: map[string]string{"shutdown": statusOK, "database": statusOK, "migrations": statusOK},
+		},
+		{
+			name: "database unavailable",
+			setup: func(health *mock_handlers.MockHealthChecker) {
+				dbErr := errors.New("failed to connect to `host=db.internal user=merch`: dial tcp 10.0.0.5:5432: connection refused")
+				health.EXPECT().Ping(gomock.Any()).Return(dbErr)
+				health.EXPECT().CheckMigrations(gomock.Any()).Return(dbErr)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"shutdown": statusOK, "database": statusFail, "migrations": statusFail},
+			expectedErrors: map[string]string{
+				"database":   "database unavailable",
+				"migrations": "database schema is not up to date",
+			},
+		},
+		{
+			name: "migrations not applied",
+			setup: func(health *mock_handlers.MockHealthChecker) {
+				health.EXPECT().Ping(gomock.Any()).Return(nil)
+				health.EXPECT().CheckMigrations(gomock.Any()).Return(errors.New("schema is behind"))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"shutdown": statusOK, "database": statusOK, "migrations": statusFail},
+			expectedErrors: map[string]string{"migrations": "database schema is not up to date"},
+		},
+		{
+			name: "shutting down",
+			setup: func(health *mock_handlers.MockHealthChecker) {
+				health.EXPECT().Ping(gomock.Any()).Return(nil)
+				health.EXPECT().CheckMigrations(gomock.Any()).Return(nil)
+			},
+			shuttingDown:   true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"shutdown": statusFail, "database": statusOK, "migrations": statusOK},
+			expectedErrors: map[string]string{"shutdown": "service is shutting down"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health := mock_handlers.NewMockHealthChecker(ctrl)
+			tt.setup(health)
+
+			handler := New(nil, health, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+			if tt.shuttingDown {
+				handler.StartShutdown()
+			}
+
+			router := mux.NewRouter()
+			handler.RegisterRoutes(router)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+
+			var response healthResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			require.Len(t, response.Checks, len(tt.expectedChecks))
+			for name, status := range tt.expectedChecks {
+				assert.Equal(t, status, response.Checks[name].Status, name)
+				assert.NotEmpty(t, response.Checks[name].Latency, name)
+				assert.Equal(t, tt.expectedErrors[name], response.Checks[name].Error, name)
+			}
+		})
+	}
+}
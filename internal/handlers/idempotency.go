@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/derticom/merch-store/internal/logging"
+	"github.com/derticom/merch-store/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// IdempotencyKeyHeader - заголовок, в котором клиент передает ключ идемпотентности.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader отмечает ответ, повторенный из сохраненного.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotent позволяет безопасно повторять запрос, изменяющий баланс: если передан Idempotency-Key,
+// запрос выполняется один раз, а повторы с тем же ключом и телом получают сохраненный ответ.
+// Запрос выполняется в одной транзакции с сохранением ответа, поэтому ответ клиенту отправляется
+// только после ее завершения. Ответы 5xx не сохраняются, и такой запрос можно повторить с тем же ключом.
+// Подключается после JWTAuthMiddleware, так как ключи хранятся отдельно для каждого пользователя.
+func (h *Handler) Idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			writeError(w, http.StatusBadRequest, "invalid idempotency key")
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		userID := r.Context().Value(userIDKey).(uuid.UUID)
+
+		var rec *bufferingRecorder
+		resp, replayed, err := h.service.ExecuteIdempotentRequest(r.Context(), userID, key, requestHash(r, body),
+			func(ctx context.Context) *models.IdempotentResponse {
+				// Транзакция может быть повторена, поэтому каждая попытка пишет в новый буфер.
+				rec = newBufferingRecorder()
+				req := r.WithContext(ctx)
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				next(rec, req)
+
+				return &models.IdempotentResponse{
+					StatusCode:  rec.status,
+					ContentType: rec.header.Get("Content-Type"),
+					Body:        rec.body.Bytes(),
+				}
+			})
+		if resp == nil {
+			h.handleError(w, r, err)
+			return
+		}
+		if err != nil {
+			ctx := r.Context()
+			logging.FromContext(ctx, h.log).ErrorContext(ctx, "failed to store idempotent response",
+				"path", r.URL.Path,
+				"error", err,
+			)
+		}
+
+		if replayed {
+			if resp.ContentType != "" {
+				w.Header().Set("Content-Type", resp.ContentType)
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+		} else {
+			for name, values := range rec.header {
+				w.Header()[name] = values
+			}
+		}
+		w.WriteHeader(resp.StatusCode)
+		w.Write(resp.Body)
+	}
+}
+
+// requestHash связывает ключ с конкретным запросом, чтобы ключ нельзя было переиспользовать для другого.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bufferingRecorder запоминает ответ обработчика, чтобы отправить его клиенту после фиксации транзакции.
+type bufferingRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferingRecorder() *bufferingRecorder {
+	return &bufferingRecorder{header: make(http.Header), status: http.StatusOK}
+}
+
+func (r *bufferingRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *bufferingRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *bufferingRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
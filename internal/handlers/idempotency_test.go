@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/handlers/mocks"
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Idempotent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_handlers.NewMockService(ctrl)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	userID := uuid.New()
+	key := "5f1c7a3e-checkout"
+	body := `{"item":"cup","quantity":2}`
+	hash := requestHash(httptest.NewRequest(http.MethodPost, "/buy", nil), []byte(body))
+	order := &models.Order{
+		ID:      uuid.MustParse("0b6e1f52-8f3a-4c2e-9d6b-2a7c1e5f4d30"),
+		Status:  models.OrderPlaced,
+		Total:   40,
+		Balance: 960,
+	}
+	orderJSON := `{"id":"0b6e1f52-8f3a-4c2e-9d6b-2a7c1e5f4d30","status":"placed","purchases":null,"total":40,"balance":960}` + "\n"
+
+	// runInTx выполняет запрос так, как это делает сервис: в контексте транзакции.
+	type txKey struct{}
+	runInTx := func(expected *models.IdempotentResponse) func(
+		context.Context, uuid.UUID, string, string, func(context.Context) *models.IdempotentResponse,
+	) (*models.IdempotentResponse, bool, error) {
+		return func(
+			ctx context.Context, _ uuid.UUID, _, _ string, run func(context.Context) *models.IdempotentResponse,
+		) (*models.IdempotentResponse, bool, error) {
+			resp := run(context.WithValue(ctx, txKey{}, true))
+			assert.Equal(t, expected, resp)
+			return resp, false, nil
+		}
+	}
+	inTx := func(ctx context.Context, _ uuid.UUID, _ string, _ int) {
+		assert.Equal(t, true, ctx.Value(txKey{}), "request must run in the idempotency transaction")
+	}
+
+	tests := []struct {
+		name             string
+		setup            func()
+		key              string
+		expectedStatus   int
+		expectedBody     string
+		expectedReplayed string
+	}{
+		{
+			name: "request without key is not tracked",
+			setup: func() {
+				mockService.EXPECT().BuyItems(gomock.Any(), userID, "cup", 2).Return(order, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   orderJSON,
+		},
+		{
+			name: "first request is executed and stored",
+			setup: func() {
+				mockService.EXPECT().ExecuteIdempotentRequest(gomock.Any(), userID, key, hash, gomock.Any()).
+					DoAndReturn(runInTx(&models.IdempotentResponse{
+						StatusCode:  http.StatusOK,
+						ContentType: "application/json",
+						Body:        []byte(orderJSON),
+					}))
+				mockService.EXPECT().BuyItems(gomock.Any(), userID, "cup", 2).Do(inTx).Return(order, nil)
+			},
+			key:            key,
+			expectedStatus: http.StatusOK,
+			expectedBody:   orderJSON,
+		},
+		{
+			name: "duplicate request is replayed",
+			setup: func() {
+				mockService.EXPECT().ExecuteIdempotentRequest(gomock.Any(), userID, key, hash, gomock.Any()).
+					Return(&models.IdempotentResponse{
+						RequestHash: hash,
+						StatusCode:  http.StatusOK,
+						ContentType: "application/json",
+						Body:        []byte(orderJSON),
+					}, true, nil)
+			},
+			key:              key,
+			expectedStatus:   http.StatusOK,
+			expectedBody:     orderJSON,
+			expectedReplayed: "true",
+		},
+		{
+			name: "key reused with different payload",
+			setup: func() {
+				mockService.EXPECT().ExecuteIdempotentRequest(gomock.Any(), userID, key, hash, gomock.Any()).
+					Return(nil, false, services.ErrIdempotencyKeyReused)
+			},
+			key:            key,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"errors":"idempotency key was used with a different request"}` + "\n",
+		},
+		{
+			name: "original request in progress",
+			setup: func() {
+				mockService.EXPECT().ExecuteIdempotentRequest(gomock.Any(), userID, key, hash, gomock.Any()).
+					Return(nil, false, services.ErrIdempotencyKeyInProgress)
+			},
+			key:            key,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"errors":"request with this idempotency key is still in progress"}` + "\n",
+		},
+		{
+			name: "internal error is returned to the client",
+			setup: func() {
+				mockService.EXPECT().ExecuteIdempotentRequest(gomock.Any(), userID, key, hash, gomock.Any()).
+					DoAndReturn(runInTx(&models.IdempotentResponse{
+						StatusCode:  http.StatusInternalServerError,
+						ContentType: "application/json",
+						Body:        []byte(`{"errors":"internal server error"}` + "\n"),
+					}))
+				mockService.EXPECT().BuyItems(gomock.Any(), userID, "cup", 2).Return(nil, errors.New("pq: deadlock detected"))
+			},
+			key:            key,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"errors":"internal server error"}` + "\n",
+		},
+		{
+			name: "response is sent even if it was not stored",
+			setup: func() {
+				mockService.EXPECT().ExecuteIdempotentRequest(gomock.Any(), userID, key, hash, gomock.Any()).
+					DoAndReturn(func(
+						ctx context.Context, _ uuid.UUID, _, _ string, run func(context.Context) *models.IdempotentResponse,
+					) (*models.IdempotentResponse, bool, error) {
+						return run(ctx), false, errors.New("connection reset")
+					})
+				mockService.EXPECT().BuyItems(gomock.Any(), userID, "cup", 2).Return(nil, services.ErrInsufficientCoins)
+			},
+			key:            key,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"errors":"insufficient coins"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req, err := http.NewRequest(http.MethodPost, "/buy", bytes.NewBufferString(body))
+			assert.NoError(t, err)
+			if tt.key != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+
+			ctx := context.WithValue(req.Context(), userIDKey, userID)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			handler.Idempotent(handler.BuyItems).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+			assert.Equal(t, tt.expectedReplayed, rr.Header().Get(IdempotentReplayedHeader))
+		})
+	}
+}
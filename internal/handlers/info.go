@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
+	"github.com/derticom/merch-store/internal/services"
+
 	"github.com/google/uuid"
 )
 
@@ -13,29 +16,30 @@ func (h *Handler) GetInfo(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.service.GetUserByID(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "user not found", http.StatusNotFound)
+		h.handleError(w, r, err)
+		return
+	}
+	if user == nil {
+		h.handleError(w, r, services.ErrUserNotFound)
 		return
 	}
 
-	transactions, err := h.service.GetTransactionHistory(r.Context(), userID)
+	coinHistory, err := h.service.GetCoinHistory(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "failed to get transaction history", http.StatusInternalServerError)
+		h.handleError(w, r, fmt.Errorf("failed to get coin history: %w", err))
 		return
 	}
 
-	purchases, err := h.service.GetPurchaseHistory(r.Context(), userID)
+	inventory, err := h.service.GetInventory(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "failed to get purchase history", http.StatusInternalServerError)
+		h.handleError(w, r, fmt.Errorf("failed to get inventory: %w", err))
 		return
 	}
 
 	response := map[string]interface{}{
-		"coins":     user.Coins,
-		"inventory": purchases,
-		"coinHistory": map[string]interface{}{
-			"received": transactions,
-			"sent":     transactions,
-		},
+		"coins":       user.Coins,
+		"inventory":   inventory,
+		"coinHistory": coinHistory,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -4,9 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
-	"strings"
 	"testing"
 
 	"github.com/derticom/merch-store/internal/handlers/mocks"
@@ -23,18 +24,21 @@ func TestHandler_GetInfo(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock_handlers.NewMockService(ctrl)
-	handler := New(mockService)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
 
 	userID := uuid.New()
 	user := &models.User{
 		ID:    userID,
 		Coins: 1000,
 	}
-	transactions := []models.Transaction{
-		{ID: uuid.New(), FromUser: userID, ToUser: uuid.New(), Amount: 100},
+	coinHistory := &models.CoinHistory{
+		Received: []models.ReceivedTransfer{{FromUser: "alice", Amount: 50}},
+		Sent:     []models.SentTransfer{{ToUser: "bob", Amount: 100}},
+		Refunds:  []models.Refund{{Item: "pen", Amount: 10}},
+		Grants:   []models.Grant{{Amount: 1000, Reason: "initial balance"}},
 	}
-	purchases := []models.Purchase{
-		{ID: uuid.New(), UserID: userID, Item: "item"},
+	inventory := []models.InventoryItem{
+		{Type: "cup", Quantity: 2, Statuses: map[string]int{models.OrderPlaced: 1, models.OrderDelivered: 1}},
 	}
 
 	tests := []struct {
@@ -48,48 +52,69 @@ func TestHandler_GetInfo(t *testing.T) {
 			name: "successful get info",
 			setup: func() {
 				mockService.EXPECT().GetUserByID(gomock.Any(), userID).Return(user, nil)
-				mockService.EXPECT().GetTransactionHistory(gomock.Any(), userID).Return(transactions, nil)
-				mockService.EXPECT().GetPurchaseHistory(gomock.Any(), userID).Return(purchases, nil)
+				mockService.EXPECT().GetCoinHistory(gomock.Any(), userID).Return(coinHistory, nil)
+				mockService.EXPECT().GetInventory(gomock.Any(), userID).Return(inventory, nil)
 			},
 			userID:         userID,
 			expectedStatus: http.StatusOK,
 			expectedBody: map[string]interface{}{
-				"coins":     user.Coins,
-				"inventory": purchases,
+				"coins": user.Coins,
+				"inventory": []map[string]interface{}{
+					{"type": "cup", "quantity": 2, "statuses": map[string]int{"placed": 1, "delivered": 1}},
+				},
 				"coinHistory": map[string]interface{}{
-					"received": transactions,
-					"sent":     transactions,
+					"received": []map[string]interface{}{
+						{"fromUser": "alice", "amount": 50},
+					},
+					"sent": []map[string]interface{}{
+						{"toUser": "bob", "amount": 100},
+					},
+					"refunds": []map[string]interface{}{
+						{"item": "pen", "amount": 10},
+					},
+					"grants": []map[string]interface{}{
+						{"amount": 1000, "reason": "initial balance"},
+					},
 				},
 			},
 		},
 		{
-			name: "user not found",
+			name: "failed to get user",
 			setup: func() {
 				mockService.EXPECT().GetUserByID(gomock.Any(), userID).Return(
-					nil, errors.New("user not found"))
+					nil, errors.New("failed to get user"))
+			},
+			userID:         userID,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   nil,
+		},
+		{
+			name: "user does not exist",
+			setup: func() {
+				mockService.EXPECT().GetUserByID(gomock.Any(), userID).Return(nil, nil)
 			},
 			userID:         userID,
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   nil,
 		},
 		{
-			name: "failed to get transaction history",
+			name: "failed to get coin history",
 			setup: func() {
 				mockService.EXPECT().GetUserByID(gomock.Any(), userID).Return(user, nil)
-				mockService.EXPECT().GetTransactionHistory(gomock.Any(), userID).Return(
-					nil, errors.New("failed to get transaction history"))
+				mockService.EXPECT().GetCoinHistory(gomock.Any(), userID).Return(
+					nil, errors.New("failed to get coin history"))
 			},
 			userID:         userID,
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   nil,
 		},
 		{
-			name: "failed to get purchase history",
+			name: "failed to get inventory",
 			setup: func() {
 				mockService.EXPECT().GetUserByID(gomock.Any(), userID).Return(user, nil)
-				mockService.EXPECT().GetTransactionHistory(gomock.Any(), userID).Return(transactions, nil)
-				mockService.EXPECT().GetPurchaseHistory(gomock.Any(), userID).Return(
-					nil, errors.New("failed to get purchase history"))
+				mockService.EXPECT().GetCoinHistory(gomock.Any(), userID).Return(coinHistory, nil)
+				mockService.EXPECT().GetInventory(gomock.Any(), userID).Return(
+					nil, errors.New("failed to get inventory"))
 			},
 			userID:         userID,
 			expectedStatus: http.StatusInternalServerError,
@@ -118,9 +143,7 @@ func TestHandler_GetInfo(t *testing.T) {
 
 			if tt.expectedStatus == http.StatusOK {
 				expectedBytes, _ := json.Marshal(tt.expectedBody)
-				actualBytes := rr.Body.Bytes()
-				actualBytes = []byte(strings.TrimSpace(string(actualBytes)))
-				assert.Equal(t, expectedBytes, actualBytes)
+				assert.JSONEq(t, string(expectedBytes), rr.Body.String())
 			}
 		})
 	}
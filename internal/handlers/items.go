@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ListItems - обработчик для получения каталога.
+//
+// Параметры запроса: minPrice и maxPrice - диапазон цен, q - подстрока названия,
+// sort - name, price, -name или -price (минус означает убывание), limit - размер страницы,
+// cursor - nextCursor из предыдущего ответа.
+func (h *Handler) ListItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := models.ItemFilter{
+		Search: query.Get("q"),
+		SortBy: strings.TrimPrefix(query.Get("sort"), "-"),
+		Desc:   strings.HasPrefix(query.Get("sort"), "-"),
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  **int
+	}{
+		{name: "minPrice", dst: &filter.MinPrice},
+		{name: "maxPrice", dst: &filter.MaxPrice},
+	} {
+		value := query.Get(p.name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid "+p.name)
+			return
+		}
+		*p.dst = &n
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	page, err := h.service.ListItems(r.Context(), filter, query.Get("cursor"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// GetItem - обработчик для получения товара по названию. Снятые с продажи товары тоже отдаются,
+// чтобы клиенты могли показать их в истории покупок.
+func (h *Handler) GetItem(w http.ResponseWriter, r *http.Request) {
+	item, err := h.service.GetItemByName(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	if item == nil {
+		h.handleError(w, r, services.ErrItemNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, item)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/handlers/mocks"
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Items(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_handlers.NewMockService(ctrl)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/items", handler.ListItems).Methods(http.MethodGet)
+	router.HandleFunc("/api/items/{name}", handler.GetItem).Methods(http.MethodGet)
+
+	minPrice, maxPrice := 10, 100
+
+	tests := []struct {
+		name           string
+		setup          func()
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "list with filters",
+			setup: func() {
+				mockService.EXPECT().ListItems(gomock.Any(), models.ItemFilter{
+					MinPrice: &minPrice,
+					MaxPrice: &maxPrice,
+					Search:   "hood",
+					SortBy:   models.ItemSortPrice,
+					Desc:     true,
+					Limit:    5,
+				}, "abc").Return(&models.ItemPage{
+					Items:      []models.Item{{Name: "hoody", Price: 300}},
+					NextCursor: "def",
+				}, nil)
+			},
+			path:           "/api/items?minPrice=10&maxPrice=100&q=hood&sort=-price&limit=5&cursor=abc",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"items":[{"name":"hoody","price":300}],"nextCursor":"def"}` + "\n",
+		},
+		{
+			name:           "invalid price",
+			setup:          func() {},
+			path:           "/api/items?minPrice=cheap",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"invalid minPrice"}` + "\n",
+		},
+		{
+			name: "invalid cursor",
+			setup: func() {
+				mockService.EXPECT().ListItems(gomock.Any(), models.ItemFilter{}, "bad").Return(nil, services.ErrInvalidCursor)
+			},
+			path:           "/api/items?cursor=bad",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"errors":"invalid cursor"}` + "\n",
+		},
+		{
+			name: "get item",
+			setup: func() {
+				mockService.EXPECT().GetItemByName(gomock.Any(), "cup").Return(&models.Item{Name: "cup", Price: 20}, nil)
+			},
+			path:           "/api/items/cup",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"cup","price":20}` + "\n",
+		},
+		{
+			name: "item not found",
+			setup: func() {
+				mockService.EXPECT().GetItemByName(gomock.Any(), "cap").Return(nil, nil)
+			},
+			path:           "/api/items/cap",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"errors":"item not found"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
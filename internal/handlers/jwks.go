@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// jwksCacheControl задает, сколько клиенты могут кешировать набор ключей. Новый ключ нужно добавить в конфигурацию
+// как ключ проверки хотя бы на это время раньше, чем начать им подписывать.
+const jwksCacheControl = "public, max-age=300"
+
+// JWKS - обработчик, отдающий публичные ключи для проверки токенов другими сервисами.
+func (h *Handler) JWKS(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", jwksCacheControl)
+	json.NewEncoder(w).Encode(h.tokens.JWKS())
+}
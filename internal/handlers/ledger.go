@@ -0,0 +1,14 @@
+package handlers
+
+import "net/http"
+
+// CheckLedger - обработчик для сверки балансов пользователей с журналом проводок.
+func (h *Handler) CheckLedger(w http.ResponseWriter, r *http.Request) {
+	check, err := h.service.CheckLedger(r.Context())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, check)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/handlers/mocks"
+	"github.com/derticom/merch-store/internal/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_CheckLedger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_handlers.NewMockService(ctrl)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	userID := uuid.MustParse("6d1f3c2a-1b4e-4f7a-9c8d-0e2b5a7c9f11")
+
+	tests := []struct {
+		name           string
+		setup          func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "consistent ledger",
+			setup: func() {
+				mockService.EXPECT().CheckLedger(gomock.Any()).Return(&models.LedgerCheck{
+					Consistent:         true,
+					Mismatches:         []models.BalanceMismatch{},
+					UnbalancedPostings: []uuid.UUID{},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"consistent":true,"mismatches":[],"unbalancedPostings":[]}` + "\n",
+		},
+		{
+			name: "balance mismatch",
+			setup: func() {
+				mockService.EXPECT().CheckLedger(gomock.Any()).Return(&models.LedgerCheck{
+					Mismatches: []models.BalanceMismatch{
+						{UserID: userID, Username: "alice", Cached: 900, Ledger: 950},
+					},
+					UnbalancedPostings: []uuid.UUID{},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"consistent":false,"mismatches":[{"userId":"6d1f3c2a-1b4e-4f7a-9c8d-0e2b5a7c9f11",` +
+				`"username":"alice","cached":900,"ledger":950}],"unbalancedPostings":[]}` + "\n",
+		},
+		{
+			name: "internal error",
+			setup: func() {
+				mockService.EXPECT().CheckLedger(gomock.Any()).Return(nil, errors.New("pq: connection refused"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"errors":"internal server error"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req, err := http.NewRequest(http.MethodGet, "/admin/ledger/check", nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.CheckLedger(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
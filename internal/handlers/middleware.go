@@ -3,69 +3,124 @@ package handlers
 import (
 	"context"
 	"net/http"
-	"os"
 	"strings"
 
+	"github.com/derticom/merch-store/internal/logging"
+	"github.com/derticom/merch-store/internal/models"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 )
 
 type contextKey string
 
-const userIDKey contextKey = "userID"
+const (
+	userIDKey         contextKey = "userID"
+	tokenIDKey        contextKey = "tokenID"
+	tokenExpiresAtKey contextKey = "tokenExpiresAt"
+	roleKey           contextKey = "role"
+)
 
-func JWTAuthMiddleware(next http.Handler) http.Handler {
+// JWTAuthMiddleware проверяет access-токен и кладет в контекст пользователя, jti и срок действия токена.
+// Токены, отозванные через logout, отклоняются до истечения их срока.
+func (h *Handler) JWTAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			http.Error(w, "missing token", http.StatusUnauthorized)
+			writeError(w, http.StatusUnauthorized, "missing token")
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, "invalid token format", http.StatusUnauthorized)
+			writeError(w, http.StatusUnauthorized, "invalid token format")
 			return
 		}
 		tokenString := parts[1]
 
-		secretKey := os.Getenv("JWT_SECRET")
-		if secretKey == "" {
-			http.Error(w, "server error: JWT secret key is not set", http.StatusInternalServerError)
-			return
-		}
-
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(secretKey), nil
-		}, jwt.WithValidMethods([]string{"HS256"}))
-
+		token, err := h.tokens.Parse(tokenString, jwt.MapClaims{})
 		if err != nil || !token.Valid {
-			http.Error(w, "invalid token", http.StatusUnauthorized)
+			writeError(w, http.StatusUnauthorized, "invalid token")
 			return
 		}
 
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			http.Error(w, "invalid token claims", http.StatusUnauthorized)
+			writeError(w, http.StatusUnauthorized, "invalid token claims")
 			return
 		}
 
 		userIDStr, ok := claims["sub"].(string)
 		if !ok {
-			http.Error(w, "invalid user ID in token", http.StatusUnauthorized)
+			writeError(w, http.StatusUnauthorized, "invalid user ID in token")
 			return
 		}
 
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
-			http.Error(w, "invalid user ID format", http.StatusUnauthorized)
+			writeError(w, http.StatusUnauthorized, "invalid user ID format")
+			return
+		}
+
+		jtiStr, ok := claims["jti"].(string)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "invalid token ID in token")
+			return
+		}
+
+		jti, err := uuid.Parse(jtiStr)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, "invalid token ID format")
 			return
 		}
 
+		expiresAt, err := claims.GetExpirationTime()
+		if err != nil || expiresAt == nil {
+			writeError(w, http.StatusUnauthorized, "invalid token expiration")
+			return
+		}
+
+		// Токены, выданные до появления ролей, получают минимальные права.
+		role, _ := claims["role"].(string)
+		if role == "" {
+			role = models.RoleEmployee
+		}
+
+		revoked, err := h.service.IsAccessTokenRevoked(r.Context(), jti)
+		if err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+		if revoked {
+			writeError(w, http.StatusUnauthorized, "token revoked")
+			return
+		}
+
+		logging.With(r.Context(), "user_id", userID.String())
+
 		ctx := context.WithValue(r.Context(), userIDKey, userID)
+		ctx = context.WithValue(ctx, tokenIDKey, jti)
+		ctx = context.WithValue(ctx, tokenExpiresAtKey, expiresAt.Time)
+		ctx = context.WithValue(ctx, roleKey, role)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// RequireRole пропускает запрос, только если роль из токена входит в roles. Подключается к подроутеру
+// после JWTAuthMiddleware, чтобы обработчикам не нужно было проверять права самим.
+func RequireRole(roles ...string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, _ := r.Context().Value(roleKey).(string)
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			writeError(w, http.StatusForbidden, "forbidden")
+		})
+	}
+}
@@ -0,0 +1,591 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/derticom/merch-store/internal/handlers (interfaces: Service)
+
+// Package mock_handlers is a generated GoMock package.
+package mock_handlers
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/derticom/merch-store/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// AddToCart mocks base method.
+func (m *MockService) AddToCart(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 int) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToCart", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddToCart indicates an expected call of AddToCart.
+func (mr *MockServiceMockRecorder) AddToCart(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToCart", reflect.TypeOf((*MockService)(nil).AddToCart), arg0, arg1, arg2, arg3)
+}
+
+// AdvanceOrder mocks base method.
+func (m *MockService) AdvanceOrder(arg0 context.Context, arg1, arg2 uuid.UUID, arg3 string) (*models.FulfillmentOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceOrder", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.FulfillmentOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceOrder indicates an expected call of AdvanceOrder.
+func (mr *MockServiceMockRecorder) AdvanceOrder(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceOrder", reflect.TypeOf((*MockService)(nil).AdvanceOrder), arg0, arg1, arg2, arg3)
+}
+
+// ApproveRefund mocks base method.
+func (m *MockService) ApproveRefund(arg0 context.Context, arg1, arg2 uuid.UUID) (*models.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveRefund", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveRefund indicates an expected call of ApproveRefund.
+func (mr *MockServiceMockRecorder) ApproveRefund(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveRefund", reflect.TypeOf((*MockService)(nil).ApproveRefund), arg0, arg1, arg2)
+}
+
+// AuthenticateUser mocks base method.
+func (m *MockService) AuthenticateUser(arg0 context.Context, arg1, arg2 string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateUser indicates an expected call of AuthenticateUser.
+func (mr *MockServiceMockRecorder) AuthenticateUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateUser", reflect.TypeOf((*MockService)(nil).AuthenticateUser), arg0, arg1, arg2)
+}
+
+// BuyItem mocks base method.
+func (m *MockService) BuyItem(arg0 context.Context, arg1 uuid.UUID, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyItem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BuyItem indicates an expected call of BuyItem.
+func (mr *MockServiceMockRecorder) BuyItem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItem", reflect.TypeOf((*MockService)(nil).BuyItem), arg0, arg1, arg2)
+}
+
+// BuyItems mocks base method.
+func (m *MockService) BuyItems(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 int) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyItems", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuyItems indicates an expected call of BuyItems.
+func (mr *MockServiceMockRecorder) BuyItems(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItems", reflect.TypeOf((*MockService)(nil).BuyItems), arg0, arg1, arg2, arg3)
+}
+
+// CheckLedger mocks base method.
+func (m *MockService) CheckLedger(arg0 context.Context) (*models.LedgerCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLedger", arg0)
+	ret0, _ := ret[0].(*models.LedgerCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckLedger indicates an expected call of CheckLedger.
+func (mr *MockServiceMockRecorder) CheckLedger(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLedger", reflect.TypeOf((*MockService)(nil).CheckLedger), arg0)
+}
+
+// Checkout mocks base method.
+func (m *MockService) Checkout(arg0 context.Context, arg1 uuid.UUID) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkout", arg0, arg1)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkout indicates an expected call of Checkout.
+func (mr *MockServiceMockRecorder) Checkout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkout", reflect.TypeOf((*MockService)(nil).Checkout), arg0, arg1)
+}
+
+// CreateItem mocks base method.
+func (m *MockService) CreateItem(arg0 context.Context, arg1 string, arg2 int) (*models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateItem indicates an expected call of CreateItem.
+func (mr *MockServiceMockRecorder) CreateItem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockService)(nil).CreateItem), arg0, arg1, arg2)
+}
+
+// ExecuteIdempotentRequest mocks base method.
+func (m *MockService) ExecuteIdempotentRequest(arg0 context.Context, arg1 uuid.UUID, arg2, arg3 string, arg4 func(context.Context) *models.IdempotentResponse) (*models.IdempotentResponse, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteIdempotentRequest", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*models.IdempotentResponse)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExecuteIdempotentRequest indicates an expected call of ExecuteIdempotentRequest.
+func (mr *MockServiceMockRecorder) ExecuteIdempotentRequest(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteIdempotentRequest", reflect.TypeOf((*MockService)(nil).ExecuteIdempotentRequest), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetAllItems mocks base method.
+func (m *MockService) GetAllItems(arg0 context.Context) ([]models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllItems", arg0)
+	ret0, _ := ret[0].([]models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllItems indicates an expected call of GetAllItems.
+func (mr *MockServiceMockRecorder) GetAllItems(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllItems", reflect.TypeOf((*MockService)(nil).GetAllItems), arg0)
+}
+
+// GetCart mocks base method.
+func (m *MockService) GetCart(arg0 context.Context, arg1 uuid.UUID) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCart", arg0, arg1)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCart indicates an expected call of GetCart.
+func (mr *MockServiceMockRecorder) GetCart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCart", reflect.TypeOf((*MockService)(nil).GetCart), arg0, arg1)
+}
+
+// GetCoinHistory mocks base method.
+func (m *MockService) GetCoinHistory(arg0 context.Context, arg1 uuid.UUID) (*models.CoinHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoinHistory", arg0, arg1)
+	ret0, _ := ret[0].(*models.CoinHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoinHistory indicates an expected call of GetCoinHistory.
+func (mr *MockServiceMockRecorder) GetCoinHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoinHistory", reflect.TypeOf((*MockService)(nil).GetCoinHistory), arg0, arg1)
+}
+
+// GetInventory mocks base method.
+func (m *MockService) GetInventory(arg0 context.Context, arg1 uuid.UUID) ([]models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInventory", arg0, arg1)
+	ret0, _ := ret[0].([]models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInventory indicates an expected call of GetInventory.
+func (mr *MockServiceMockRecorder) GetInventory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInventory", reflect.TypeOf((*MockService)(nil).GetInventory), arg0, arg1)
+}
+
+// GetItemByName mocks base method.
+func (m *MockService) GetItemByName(arg0 context.Context, arg1 string) (*models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemByName", arg0, arg1)
+	ret0, _ := ret[0].(*models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemByName indicates an expected call of GetItemByName.
+func (mr *MockServiceMockRecorder) GetItemByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemByName", reflect.TypeOf((*MockService)(nil).GetItemByName), arg0, arg1)
+}
+
+// GetPurchaseHistory mocks base method.
+func (m *MockService) GetPurchaseHistory(arg0 context.Context, arg1 uuid.UUID) ([]models.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchaseHistory", arg0, arg1)
+	ret0, _ := ret[0].([]models.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchaseHistory indicates an expected call of GetPurchaseHistory.
+func (mr *MockServiceMockRecorder) GetPurchaseHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchaseHistory", reflect.TypeOf((*MockService)(nil).GetPurchaseHistory), arg0, arg1)
+}
+
+// GetRefundRequests mocks base method.
+func (m *MockService) GetRefundRequests(arg0 context.Context) ([]models.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefundRequests", arg0)
+	ret0, _ := ret[0].([]models.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefundRequests indicates an expected call of GetRefundRequests.
+func (mr *MockServiceMockRecorder) GetRefundRequests(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefundRequests", reflect.TypeOf((*MockService)(nil).GetRefundRequests), arg0)
+}
+
+// GetStockMovements mocks base method.
+func (m *MockService) GetStockMovements(arg0 context.Context, arg1 string) ([]models.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockMovements", arg0, arg1)
+	ret0, _ := ret[0].([]models.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockMovements indicates an expected call of GetStockMovements.
+func (mr *MockServiceMockRecorder) GetStockMovements(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockMovements", reflect.TypeOf((*MockService)(nil).GetStockMovements), arg0, arg1)
+}
+
+// GetTransactionHistory mocks base method.
+func (m *MockService) GetTransactionHistory(arg0 context.Context, arg1 uuid.UUID) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionHistory", arg0, arg1)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionHistory indicates an expected call of GetTransactionHistory.
+func (mr *MockServiceMockRecorder) GetTransactionHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionHistory", reflect.TypeOf((*MockService)(nil).GetTransactionHistory), arg0, arg1)
+}
+
+// GetUserByID mocks base method.
+func (m *MockService) GetUserByID(arg0 context.Context, arg1 uuid.UUID) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockServiceMockRecorder) GetUserByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockService)(nil).GetUserByID), arg0, arg1)
+}
+
+// GrantCoins mocks base method.
+func (m *MockService) GrantCoins(arg0 context.Context, arg1 uuid.UUID, arg2 []models.CoinGrant) (*models.CoinGrantBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantCoins", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.CoinGrantBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GrantCoins indicates an expected call of GrantCoins.
+func (mr *MockServiceMockRecorder) GrantCoins(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantCoins", reflect.TypeOf((*MockService)(nil).GrantCoins), arg0, arg1, arg2)
+}
+
+// IsAccessTokenRevoked mocks base method.
+func (m *MockService) IsAccessTokenRevoked(arg0 context.Context, arg1 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAccessTokenRevoked", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAccessTokenRevoked indicates an expected call of IsAccessTokenRevoked.
+func (mr *MockServiceMockRecorder) IsAccessTokenRevoked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAccessTokenRevoked", reflect.TypeOf((*MockService)(nil).IsAccessTokenRevoked), arg0, arg1)
+}
+
+// IssueRefreshToken mocks base method.
+func (m *MockService) IssueRefreshToken(arg0 context.Context, arg1 uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueRefreshToken", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueRefreshToken indicates an expected call of IssueRefreshToken.
+func (mr *MockServiceMockRecorder) IssueRefreshToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueRefreshToken", reflect.TypeOf((*MockService)(nil).IssueRefreshToken), arg0, arg1)
+}
+
+// ListItems mocks base method.
+func (m *MockService) ListItems(arg0 context.Context, arg1 models.ItemFilter, arg2 string) (*models.ItemPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItems", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ItemPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItems indicates an expected call of ListItems.
+func (mr *MockServiceMockRecorder) ListItems(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItems", reflect.TypeOf((*MockService)(nil).ListItems), arg0, arg1, arg2)
+}
+
+// ListOrders mocks base method.
+func (m *MockService) ListOrders(arg0 context.Context, arg1 string) ([]models.FulfillmentOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", arg0, arg1)
+	ret0, _ := ret[0].([]models.FulfillmentOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockServiceMockRecorder) ListOrders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockService)(nil).ListOrders), arg0, arg1)
+}
+
+// Logout mocks base method.
+func (m *MockService) Logout(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 uuid.UUID, arg4 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockServiceMockRecorder) Logout(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockService)(nil).Logout), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RefreshSession mocks base method.
+func (m *MockService) RefreshSession(arg0 context.Context, arg1 string) (*models.User, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshSession", arg0, arg1)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RefreshSession indicates an expected call of RefreshSession.
+func (mr *MockServiceMockRecorder) RefreshSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSession", reflect.TypeOf((*MockService)(nil).RefreshSession), arg0, arg1)
+}
+
+// RegisterUser mocks base method.
+func (m *MockService) RegisterUser(arg0 context.Context, arg1, arg2, arg3 string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterUser", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterUser indicates an expected call of RegisterUser.
+func (mr *MockServiceMockRecorder) RegisterUser(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockService)(nil).RegisterUser), arg0, arg1, arg2, arg3)
+}
+
+// RemoveFromCart mocks base method.
+func (m *MockService) RemoveFromCart(arg0 context.Context, arg1 uuid.UUID, arg2 string) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromCart", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveFromCart indicates an expected call of RemoveFromCart.
+func (mr *MockServiceMockRecorder) RemoveFromCart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromCart", reflect.TypeOf((*MockService)(nil).RemoveFromCart), arg0, arg1, arg2)
+}
+
+// RequestRefund mocks base method.
+func (m *MockService) RequestRefund(arg0 context.Context, arg1, arg2 uuid.UUID) (*models.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestRefund", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestRefund indicates an expected call of RequestRefund.
+func (mr *MockServiceMockRecorder) RequestRefund(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestRefund", reflect.TypeOf((*MockService)(nil).RequestRefund), arg0, arg1, arg2)
+}
+
+// RestockItem mocks base method.
+func (m *MockService) RestockItem(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 int, arg4 string) (*models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestockItem", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestockItem indicates an expected call of RestockItem.
+func (mr *MockServiceMockRecorder) RestockItem(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestockItem", reflect.TypeOf((*MockService)(nil).RestockItem), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RetireItem mocks base method.
+func (m *MockService) RetireItem(arg0 context.Context, arg1 string) (*models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireItem", arg0, arg1)
+	ret0, _ := ret[0].(*models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetireItem indicates an expected call of RetireItem.
+func (mr *MockServiceMockRecorder) RetireItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireItem", reflect.TypeOf((*MockService)(nil).RetireItem), arg0, arg1)
+}
+
+// SendCoins mocks base method.
+func (m *MockService) SendCoins(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendCoins", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendCoins indicates an expected call of SendCoins.
+func (mr *MockServiceMockRecorder) SendCoins(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendCoins", reflect.TypeOf((*MockService)(nil).SendCoins), arg0, arg1, arg2, arg3)
+}
+
+// SetItemStock mocks base method.
+func (m *MockService) SetItemStock(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 *int, arg4 string) (*models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetItemStock", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetItemStock indicates an expected call of SetItemStock.
+func (mr *MockServiceMockRecorder) SetItemStock(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetItemStock", reflect.TypeOf((*MockService)(nil).SetItemStock), arg0, arg1, arg2, arg3, arg4)
+}
+
+// SetUserRole mocks base method.
+func (m *MockService) SetUserRole(arg0 context.Context, arg1, arg2 string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRole", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserRole indicates an expected call of SetUserRole.
+func (mr *MockServiceMockRecorder) SetUserRole(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRole", reflect.TypeOf((*MockService)(nil).SetUserRole), arg0, arg1, arg2)
+}
+
+// UpdateItem mocks base method.
+func (m *MockService) UpdateItem(arg0 context.Context, arg1 string, arg2 models.ItemUpdate) (*models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItem indicates an expected call of UpdateItem.
+func (mr *MockServiceMockRecorder) UpdateItem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockService)(nil).UpdateItem), arg0, arg1, arg2)
+}
+
+// UpdateUserCoins mocks base method.
+func (m *MockService) UpdateUserCoins(arg0 context.Context, arg1 uuid.UUID, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserCoins", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserCoins indicates an expected call of UpdateUserCoins.
+func (mr *MockServiceMockRecorder) UpdateUserCoins(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserCoins", reflect.TypeOf((*MockService)(nil).UpdateUserCoins), arg0, arg1, arg2)
+}
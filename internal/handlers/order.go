@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListOrders - обработчик для получения заказов склада, параметр status фильтрует их по статусу.
+func (h *Handler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	orders, err := h.service.ListOrders(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, orders)
+}
+
+// AdvanceOrder возвращает обработчик, который переводит заказ в статус to от имени текущего пользователя.
+func (h *Handler) AdvanceOrder(to string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orderID, err := uuid.Parse(mux.Vars(r)["id"])
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid order id")
+			return
+		}
+
+		actorID := r.Context().Value(userIDKey).(uuid.UUID)
+
+		order, err := h.service.AdvanceOrder(r.Context(), actorID, orderID, to)
+		if err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, order)
+	}
+}
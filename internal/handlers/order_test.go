@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/handlers/mocks"
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_AdvanceOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_handlers.NewMockService(ctrl)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), newTestTokens(t))
+
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	actorID := uuid.New()
+	orderID := uuid.New()
+
+	tests := []struct {
+		name           string
+		role           string
+		setup          func()
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "warehouse packs order",
+			role: models.RoleWarehouse,
+			setup: func() {
+				mockService.EXPECT().AdvanceOrder(gomock.Any(), actorID, orderID, models.OrderPacked).
+					Return(&models.FulfillmentOrder{ID: orderID, Status: models.OrderPacked}, nil)
+			},
+			path:           "/api/warehouse/orders/" + orderID.String() + "/pack",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "admin hands order over",
+			role: models.RoleAdmin,
+			setup: func() {
+				mockService.EXPECT().AdvanceOrder(gomock.Any(), actorID, orderID, models.OrderDelivered).
+					Return(&models.FulfillmentOrder{ID: orderID, Status: models.OrderDelivered}, nil)
+			},
+			path:           "/api/warehouse/orders/" + orderID.String() + "/deliver",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "invalid transition",
+			role: models.RoleWarehouse,
+			setup: func() {
+				mockService.EXPECT().AdvanceOrder(gomock.Any(), actorID, orderID, models.OrderCancelled).
+					Return(nil, services.ErrInvalidOrderTransition)
+			},
+			path:           "/api/warehouse/orders/" + orderID.String() + "/cancel",
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"errors":"order cannot be moved to this status"}` + "\n",
+		},
+		{
+			name:           "invalid order id",
+			role:           models.RoleWarehouse,
+			setup:          func() {},
+			path:           "/api/warehouse/orders/42/ready",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"invalid order id"}` + "\n",
+		},
+		{
+			name:           "employee is forbidden",
+			role:           models.RoleEmployee,
+			setup:          func() {},
+			path:           "/api/warehouse/orders/" + orderID.String() + "/pack",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			mockService.EXPECT().IsAccessTokenRevoked(gomock.Any(), gomock.Any()).Return(false, nil)
+
+			token, err := handler.generateJWT(&models.User{ID: actorID, Role: tt.role})
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, tt.path, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
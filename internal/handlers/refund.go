@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/derticom/merch-store/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetPurchases - обработчик для получения истории покупок пользователя со статусами возврата.
+func (h *Handler) GetPurchases(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(uuid.UUID)
+
+	purchases, err := h.service.GetPurchaseHistory(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if purchases == nil {
+		purchases = []models.Purchase{}
+	}
+
+	writeJSON(w, http.StatusOK, purchases)
+}
+
+// RequestRefund - обработчик для подачи заявки на возврат покупки.
+func (h *Handler) RequestRefund(w http.ResponseWriter, r *http.Request) {
+	purchaseID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid purchase id")
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(uuid.UUID)
+
+	purchase, err := h.service.RequestRefund(r.Context(), userID, purchaseID)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, purchase)
+}
+
+// GetRefundRequests - обработчик для получения заявок на возврат, ожидающих одобрения.
+func (h *Handler) GetRefundRequests(w http.ResponseWriter, r *http.Request) {
+	purchases, err := h.service.GetRefundRequests(r.Context())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if purchases == nil {
+		purchases = []models.Purchase{}
+	}
+
+	writeJSON(w, http.StatusOK, purchases)
+}
+
+// ApproveRefund - обработчик для одобрения возврата администратором.
+func (h *Handler) ApproveRefund(w http.ResponseWriter, r *http.Request) {
+	adminID := r.Context().Value(userIDKey).(uuid.UUID)
+
+	purchaseID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid purchase id")
+		return
+	}
+
+	purchase, err := h.service.ApproveRefund(r.Context(), adminID, purchaseID)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, purchase)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/handlers/mocks"
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Refunds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_handlers.NewMockService(ctrl)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/purchases/{id}/refund", handler.RequestRefund).Methods(http.MethodPost)
+	router.HandleFunc("/admin/refunds/{id}/approve", handler.ApproveRefund).Methods(http.MethodPost)
+
+	userID := uuid.New()
+	purchaseID := uuid.New()
+
+	tests := []struct {
+		name           string
+		setup          func()
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "refund requested",
+			setup: func() {
+				mockService.EXPECT().RequestRefund(gomock.Any(), userID, purchaseID).Return(&models.Purchase{
+					ID:     purchaseID,
+					UserID: userID,
+					Item:   "cup",
+					Price:  20,
+					Status: models.PurchaseRefundRequested,
+				}, nil)
+			},
+			path:           "/purchases/" + purchaseID.String() + "/refund",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid purchase id",
+			setup:          func() {},
+			path:           "/purchases/42/refund",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"invalid purchase id"}` + "\n",
+		},
+		{
+			name: "refund window expired",
+			setup: func() {
+				mockService.EXPECT().RequestRefund(gomock.Any(), userID, purchaseID).
+					Return(nil, services.ErrRefundWindowExpired)
+			},
+			path:           "/purchases/" + purchaseID.String() + "/refund",
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"errors":"refund window has expired"}` + "\n",
+		},
+		{
+			name: "purchase not found",
+			setup: func() {
+				mockService.EXPECT().RequestRefund(gomock.Any(), userID, purchaseID).
+					Return(nil, services.ErrPurchaseNotFound)
+			},
+			path:           "/purchases/" + purchaseID.String() + "/refund",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"errors":"purchase not found"}` + "\n",
+		},
+		{
+			name: "refund approved",
+			setup: func() {
+				mockService.EXPECT().ApproveRefund(gomock.Any(), userID, purchaseID).Return(&models.Purchase{
+					ID:     purchaseID,
+					UserID: userID,
+					Item:   "cup",
+					Price:  20,
+					Status: models.PurchaseRefunded,
+				}, nil)
+			},
+			path:           "/admin/refunds/" + purchaseID.String() + "/approve",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "refund not requested",
+			setup: func() {
+				mockService.EXPECT().ApproveRefund(gomock.Any(), userID, purchaseID).Return(nil, services.ErrRefundNotRequested)
+			},
+			path:           "/admin/refunds/" + purchaseID.String() + "/approve",
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"errors":"refund was not requested for this purchase"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req, err := http.NewRequest(http.MethodPost, tt.path, nil)
+			assert.NoError(t, err)
+
+			ctx := context.WithValue(req.Context(), userIDKey, userID)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -1,17 +1,64 @@
 package handlers
 
 import (
+	"github.com/derticom/merch-store/internal/models"
+
 	"github.com/gorilla/mux"
 )
 
 func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/healthz", h.Healthz).Methods("GET")
+	router.HandleFunc("/readyz", h.Readyz).Methods("GET")
+	router.HandleFunc("/.well-known/jwks.json", h.JWKS).Methods("GET")
+
 	api := router.PathPrefix("/api").Subrouter()
 	api.HandleFunc("/auth/register", h.Register).Methods("POST")
 	api.HandleFunc("/auth/login", h.Login).Methods("POST")
+	api.HandleFunc("/auth/refresh", h.Refresh).Methods("POST")
+	api.HandleFunc("/items", h.ListItems).Methods("GET")
+	api.HandleFunc("/items/{name}", h.GetItem).Methods("GET")
 
 	protected := router.PathPrefix("/api").Subrouter()
-	protected.Use(JWTAuthMiddleware)
+	protected.Use(h.JWTAuthMiddleware)
+	protected.HandleFunc("/auth/logout", h.Logout).Methods("POST")
 	protected.HandleFunc("/info", h.GetInfo).Methods("GET")
-	protected.HandleFunc("/sendCoin", h.SendCoin).Methods("POST")
-	protected.HandleFunc("/buy/{item}", h.BuyItem).Methods("GET")
+	protected.HandleFunc("/sendCoin", h.Idempotent(h.SendCoin)).Methods("POST")
+	protected.HandleFunc("/buy", h.Idempotent(h.BuyItems)).Methods("POST")
+	protected.HandleFunc("/cart", h.GetCart).Methods("GET")
+	protected.HandleFunc("/cart/items", h.AddToCart).Methods("POST")
+	protected.HandleFunc("/cart/items/{item}", h.RemoveFromCart).Methods("DELETE")
+	protected.HandleFunc("/cart/checkout", h.Idempotent(h.Checkout)).Methods("POST")
+	protected.HandleFunc("/purchases", h.GetPurchases).Methods("GET")
+	protected.HandleFunc("/purchases/{id}/refund", h.RequestRefund).Methods("POST")
+	if h.legacyBuy {
+		protected.HandleFunc("/buy/{item}", h.deprecatedBuy(h.Idempotent(h.BuyItem))).Methods("GET")
+	}
+
+	admin := router.PathPrefix("/api/admin").Subrouter()
+	admin.Use(h.JWTAuthMiddleware, RequireRole(models.RoleAdmin))
+	admin.HandleFunc("/users/{username}/role", h.SetUserRole).Methods("PUT")
+	admin.HandleFunc("/users/{username}/coins", h.Idempotent(h.GrantUserCoins)).Methods("POST")
+	admin.HandleFunc("/coins/grants", h.Idempotent(h.GrantCoinsBatch)).Methods("POST")
+	admin.HandleFunc("/items", h.CreateItem).Methods("POST")
+	admin.HandleFunc("/items/{name}", h.UpdateItem).Methods("PATCH")
+	admin.HandleFunc("/items/{name}", h.RetireItem).Methods("DELETE")
+	admin.HandleFunc("/items/{name}/restock", h.RestockItem).Methods("POST")
+	admin.HandleFunc("/items/{name}/stock", h.SetItemStock).Methods("PUT")
+	admin.HandleFunc("/refunds/{id}/approve", h.ApproveRefund).Methods("POST")
+
+	// Сборкой и выдачей заказов занимается склад; администраторы могут его подменить.
+	warehouse := router.PathPrefix("/api/warehouse").Subrouter()
+	warehouse.Use(h.JWTAuthMiddleware, RequireRole(models.RoleWarehouse, models.RoleAdmin))
+	warehouse.HandleFunc("/orders", h.ListOrders).Methods("GET")
+	warehouse.HandleFunc("/orders/{id}/pack", h.AdvanceOrder(models.OrderPacked)).Methods("POST")
+	warehouse.HandleFunc("/orders/{id}/ready", h.AdvanceOrder(models.OrderReadyForPickup)).Methods("POST")
+	warehouse.HandleFunc("/orders/{id}/deliver", h.AdvanceOrder(models.OrderDelivered)).Methods("POST")
+	warehouse.HandleFunc("/orders/{id}/cancel", h.AdvanceOrder(models.OrderCancelled)).Methods("POST")
+
+	// Чтение журналов доступно и аудиторам.
+	audit := router.PathPrefix("/api/admin").Subrouter()
+	audit.Use(h.JWTAuthMiddleware, RequireRole(models.RoleAdmin, models.RoleAuditor))
+	audit.HandleFunc("/items/{name}/stock-movements", h.GetStockMovements).Methods("GET")
+	audit.HandleFunc("/refunds", h.GetRefundRequests).Methods("GET")
+	audit.HandleFunc("/ledger/check", h.CheckLedger).Methods("GET")
 }
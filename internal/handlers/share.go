@@ -14,19 +14,20 @@ func (h *Handler) SendCoin(w http.ResponseWriter, r *http.Request) {
 		Amount int    `json:"amount"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	fromUserID := r.Context().Value(userIDKey).(uuid.UUID)
-	toUserID, err := uuid.Parse(req.ToUser)
-	if err != nil {
-		http.Error(w, "invalid user ID", http.StatusBadRequest)
+	// toUser - имя получателя, для обратной совместимости также принимается его ID.
+	if req.ToUser == "" {
+		writeError(w, http.StatusBadRequest, "recipient is required")
 		return
 	}
 
-	if err := h.service.SendCoins(r.Context(), fromUserID, toUserID, req.Amount); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	fromUserID := r.Context().Value(userIDKey).(uuid.UUID)
+
+	if err := h.service.SendCoins(r.Context(), fromUserID, req.ToUser, req.Amount); err != nil {
+		h.handleError(w, r, err)
 		return
 	}
 
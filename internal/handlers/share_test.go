@@ -5,11 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/derticom/merch-store/internal/handlers/mocks"
+	"github.com/derticom/merch-store/internal/services"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -22,10 +25,10 @@ func TestHandler_SendCoin(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock_handlers.NewMockService(ctrl)
-	handler := New(mockService)
+	handler := New(mockService, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
 
 	fromUserID := uuid.New()
-	toUserID := uuid.New()
+	toUser := "recipient"
 	amount := 100
 
 	tests := []struct {
@@ -39,10 +42,10 @@ func TestHandler_SendCoin(t *testing.T) {
 		{
 			name: "successful send coin",
 			setup: func() {
-				mockService.EXPECT().SendCoins(gomock.Any(), fromUserID, toUserID, amount).Return(nil)
+				mockService.EXPECT().SendCoins(gomock.Any(), fromUserID, toUser, amount).Return(nil)
 			},
 			requestBody: map[string]interface{}{
-				"toUser": toUserID.String(),
+				"toUser": toUser,
 				"amount": amount,
 			},
 			userID:         fromUserID,
@@ -55,44 +58,69 @@ func TestHandler_SendCoin(t *testing.T) {
 			requestBody:    nil,
 			userID:         fromUserID,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "invalid request body\n",
+			expectedBody:   `{"errors":"invalid request body"}` + "\n",
 		},
 		{
-			name:  "invalid user ID",
+			name:  "missing recipient",
 			setup: func() {},
 			requestBody: map[string]interface{}{
-				"toUser": "invalid-uuid",
 				"amount": amount,
 			},
 			userID:         fromUserID,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "invalid user ID\n",
+			expectedBody:   `{"errors":"recipient is required"}` + "\n",
+		},
+		{
+			name: "recipient not found",
+			setup: func() {
+				mockService.EXPECT().SendCoins(gomock.Any(), fromUserID, toUser, amount).Return(services.ErrRecipientNotFound)
+			},
+			requestBody: map[string]interface{}{
+				"toUser": toUser,
+				"amount": amount,
+			},
+			userID:         fromUserID,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"errors":"recipient not found"}` + "\n",
 		},
 		{
 			name: "insufficient coins",
 			setup: func() {
-				mockService.EXPECT().SendCoins(gomock.Any(), fromUserID, toUserID, amount).Return(errors.New("insufficient coins"))
+				mockService.EXPECT().SendCoins(gomock.Any(), fromUserID, toUser, amount).Return(services.ErrInsufficientCoins)
 			},
 			requestBody: map[string]interface{}{
-				"toUser": toUserID.String(),
+				"toUser": toUser,
 				"amount": amount,
 			},
 			userID:         fromUserID,
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "insufficient coins\n",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"errors":"insufficient coins"}` + "\n",
 		},
 		{
-			name: "user not found",
+			name: "non-positive amount",
 			setup: func() {
-				mockService.EXPECT().SendCoins(gomock.Any(), fromUserID, toUserID, amount).Return(errors.New("user not found"))
+				mockService.EXPECT().SendCoins(gomock.Any(), fromUserID, toUser, 0).Return(services.ErrNonPositiveAmount)
 			},
 			requestBody: map[string]interface{}{
-				"toUser": toUserID.String(),
+				"toUser": toUser,
+				"amount": 0,
+			},
+			userID:         fromUserID,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"errors":"amount must be positive"}` + "\n",
+		},
+		{
+			name: "internal error is not exposed",
+			setup: func() {
+				mockService.EXPECT().SendCoins(gomock.Any(), fromUserID, toUser, amount).Return(errors.New("pq: deadlock detected"))
+			},
+			requestBody: map[string]interface{}{
+				"toUser": toUser,
 				"amount": amount,
 			},
 			userID:         fromUserID,
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "user not found\n",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"errors":"internal server error"}` + "\n",
 		},
 	}
 
@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader - заголовок, в котором передается идентификатор запроса.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength ограничивает длину входящего идентификатора, чтобы клиент не мог раздувать логи.
+const maxRequestIDLength = 128
+
+type contextKey struct{}
+
+// requestLogger хранит логгер запроса. Передается по указателю, чтобы внутренние middleware
+// (например, авторизация) могли дополнить его атрибутами, которые попадут и в access-лог.
+type requestLogger struct {
+	log *slog.Logger
+}
+
+// FromContext возвращает логгер текущего запроса или fallback, если запрос не проходил через Middleware.
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if rl, ok := ctx.Value(contextKey{}).(*requestLogger); ok {
+		return rl.log
+	}
+
+	return fallback
+}
+
+// With добавляет атрибуты к логгеру текущего запроса.
+func With(ctx context.Context, args ...any) {
+	if rl, ok := ctx.Value(contextKey{}).(*requestLogger); ok {
+		rl.log = rl.log.With(args...)
+	}
+}
+
+// Middleware назначает запросу X-Request-ID (или использует переданный клиентом), кладет в контекст
+// логгер запроса и пишет одну строку access-лога по завершении. Подключается через mux.Router.Use,
+// чтобы в логе был шаблон маршрута.
+func Middleware(log *slog.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(RequestIDHeader)
+			if requestID == "" || len(requestID) > maxRequestIDLength {
+				requestID = uuid.NewString()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			reqLog := log.With("request_id", requestID)
+			if spanCtx := trace.SpanContextFromContext(r.Context()); spanCtx.HasTraceID() {
+				reqLog = reqLog.With("trace_id", spanCtx.TraceID().String())
+			}
+			rl := &requestLogger{log: reqLog}
+			ctx := context.WithValue(r.Context(), contextKey{}, rl)
+
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			rl.log.InfoContext(ctx, "request",
+				"method", r.Method,
+				"route", routeTemplate(r),
+				"path", r.URL.Path,
+				"status", rec.status,
+				"bytes", rec.bytes,
+				"duration", time.Since(start),
+			)
+		})
+	}
+}
+
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+
+	return template
+}
+
+// responseRecorder запоминает код ответа и число отправленных байт.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
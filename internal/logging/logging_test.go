@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter(buf *bytes.Buffer) *mux.Router {
+	log := slog.New(slog.NewJSONHandler(buf, nil))
+
+	router := mux.NewRouter()
+	router.Use(Middleware(log))
+	router.HandleFunc("/api/buy/{item}", func(w http.ResponseWriter, r *http.Request) {
+		With(r.Context(), "user_id", "42")
+		FromContext(r.Context(), nil).InfoContext(r.Context(), "buying")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	})
+
+	return router
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+
+	return lines
+}
+
+func TestMiddleware_AccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	router := newTestRouter(&buf)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/buy/cup", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	requestID := rr.Header().Get(RequestIDHeader)
+	assert.NotEmpty(t, requestID)
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+
+	handlerLine, accessLine := lines[0], lines[1]
+	assert.Equal(t, requestID, handlerLine["request_id"])
+	assert.Equal(t, "42", handlerLine["user_id"])
+
+	assert.Equal(t, "request", accessLine["msg"])
+	assert.Equal(t, requestID, accessLine["request_id"])
+	assert.Equal(t, "42", accessLine["user_id"], "attributes added by inner middleware must reach the access log")
+	assert.Equal(t, http.MethodGet, accessLine["method"])
+	assert.Equal(t, "/api/buy/{item}", accessLine["route"])
+	assert.Equal(t, "/api/buy/cup", accessLine["path"])
+	assert.EqualValues(t, http.StatusCreated, accessLine["status"])
+	assert.EqualValues(t, len("hello"), accessLine["bytes"])
+	assert.Contains(t, accessLine, "duration")
+}
+
+func TestMiddleware_PropagatesRequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		keep      bool
+	}{
+		{name: "incoming id is kept", requestID: "abc-123", keep: true},
+		{name: "too long id is replaced", requestID: strings.Repeat("a", maxRequestIDLength+1), keep: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			router := newTestRouter(&buf)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/buy/cup", nil)
+			req.Header.Set(RequestIDHeader, tt.requestID)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			got := rr.Header().Get(RequestIDHeader)
+			if tt.keep {
+				assert.Equal(t, tt.requestID, got)
+			} else {
+				assert.NotEqual(t, tt.requestID, got)
+				assert.NotEmpty(t, got)
+			}
+		})
+	}
+}
+
+func TestFromContext_Fallback(t *testing.T) {
+	fallback := slog.Default()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	assert.Same(t, fallback, FromContext(req.Context(), fallback))
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(Middleware)
+	router.HandleFunc("/api/buy/{item}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods(http.MethodGet)
+
+	before := testutil.ToFloat64(httpRequestsTotal.WithLabelValues(http.MethodGet, "/api/buy/{item}", "404"))
+
+	for _, item := range []string{"cup", "pen"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/buy/"+item, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	after := testutil.ToFloat64(httpRequestsTotal.WithLabelValues(http.MethodGet, "/api/buy/{item}", "404"))
+	assert.Equal(t, 2.0, after-before, "requests must be grouped by route template")
+}
+
+type stubStats sql.DBStats
+
+func (s stubStats) Stats() sql.DBStats {
+	return sql.DBStats(s)
+}
+
+func TestDBStatsCollector(t *testing.T) {
+	collector := newDBStatsCollector(stubStats{
+		MaxOpenConnections: 10,
+		OpenConnections:    3,
+		InUse:              2,
+		Idle:               1,
+		WaitCount:          5,
+		WaitDuration:       2 * time.Second,
+	})
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	expected := `
+# HELP merch_store_db_in_use_connections Number of connections currently in use.
+# TYPE merch_store_db_in_use_connections gauge
+merch_store_db_in_use_connections 2
+# HELP merch_store_db_open_connections Number of established connections, both in use and idle.
+# TYPE merch_store_db_open_connections gauge
+merch_store_db_open_connections 3
+# HELP merch_store_db_wait_duration_seconds_total Total time blocked waiting for a new connection.
+# TYPE merch_store_db_wait_duration_seconds_total counter
+merch_store_db_wait_duration_seconds_total 2
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"merch_store_db_in_use_connections",
+		"merch_store_db_open_connections",
+		"merch_store_db_wait_duration_seconds_total",
+	)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,17 @@
+package models
+
+// CartLine - строка корзины с текущей ценой товара.
+type CartLine struct {
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+	Price    int    `json:"price"`
+	Subtotal int    `json:"subtotal"`
+	// Available - можно ли купить товар сейчас: он не снят с продажи и остатка хватает.
+	Available bool `json:"available"`
+}
+
+// Cart - корзина пользователя. Цены не фиксируются: при оформлении заказа используются актуальные.
+type Cart struct {
+	Items []CartLine `json:"items"`
+	Total int        `json:"total"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "github.com/google/uuid"
+
+// CoinGrant - начисление (положительная сумма) или списание (отрицательная) монет пользователю администратором.
+type CoinGrant struct {
+	Username string `json:"username"`
+	Amount   int    `json:"amount"`
+	Reason   string `json:"reason"`
+}
+
+// CoinGrantResult - примененное начисление и баланс пользователя после него.
+type CoinGrantResult struct {
+	Username string `json:"username"`
+	Amount   int    `json:"amount"`
+	Reason   string `json:"reason"`
+	Balance  int    `json:"balance"`
+}
+
+// CoinGrantBatch - начисления, выполненные в одной транзакции. ID записывается в проводки как reference_id.
+type CoinGrantBatch struct {
+	ID     uuid.UUID         `json:"id"`
+	Grants []CoinGrantResult `json:"grants"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// IdempotentResponse - сохраненный ответ на запрос с ключом идемпотентности.
+type IdempotentResponse struct {
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
@@ -1,6 +1,80 @@
 package models
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type Item struct {
 	Name  string `json:"name" db:"name"`
 	Price int    `json:"price" db:"price"`
+	// RetiredAt - когда товар сняли с продажи. Снятый товар нельзя купить, но он остается в истории покупок.
+	RetiredAt *time.Time `json:"retiredAt,omitempty" db:"retired_at"`
+	// Stock - остаток товара; nil означает, что количество не ограничено.
+	Stock *int `json:"stock,omitempty" db:"stock"`
+	// PerUserLimit - сколько единиц товара может купить один пользователь; nil - без ограничений.
+	PerUserLimit *int `json:"perUserLimit,omitempty" db:"per_user_limit"`
+}
+
+// ItemUpdate - изменяемые поля товара. Незаданные (nil) поля не меняются.
+type ItemUpdate struct {
+	Name  *string
+	Price *int
+	// PerUserLimit - новое ограничение на пользователя; 0 снимает ограничение.
+	PerUserLimit *int
+}
+
+// Виды движений остатков.
+const (
+	StockMovementPurchase = "purchase"
+	StockMovementRestock  = "restock"
+	StockMovementRefund   = "refund"
+	// StockMovementAdjustment - администратор задал остаток напрямую, например после инвентаризации.
+	StockMovementAdjustment = "adjustment"
+)
+
+// StockMovement - изменение остатка товара: покупка, пополнение или корректировка администратором, возврат.
+type StockMovement struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	Item       string     `json:"item" db:"item"`
+	Delta      int        `json:"delta" db:"delta"`
+	Kind       string     `json:"kind" db:"kind"`
+	UserID     *uuid.UUID `json:"userId,omitempty" db:"user_id"`
+	PurchaseID *uuid.UUID `json:"purchaseId,omitempty" db:"purchase_id"`
+	Note       string     `json:"note,omitempty" db:"note"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// Поля, по которым можно сортировать каталог.
+const (
+	ItemSortName  = "name"
+	ItemSortPrice = "price"
+)
+
+// ItemFilter - параметры выборки из каталога. Снятые с продажи товары в выборку не попадают.
+type ItemFilter struct {
+	MinPrice *int
+	MaxPrice *int
+	// Search - подстрока названия, без учета регистра.
+	Search string
+	SortBy string
+	Desc   bool
+	Limit  int
+	// After - последний товар предыдущей страницы.
+	After *ItemCursor
+}
+
+// ItemCursor - позиция в каталоге, с которой начинается следующая страница.
+type ItemCursor struct {
+	SortBy string `json:"s"`
+	Desc   bool   `json:"d"`
+	Name   string `json:"n"`
+	Price  int    `json:"p"`
+}
+
+// ItemPage - страница каталога. NextCursor пуст на последней странице.
+type ItemPage struct {
+	Items      []Item `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
 }
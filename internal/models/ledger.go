@@ -0,0 +1,82 @@
+package models
+
+import "github.com/google/uuid"
+
+// Виды проводок журнала.
+const (
+	PostingGrant      = "grant"
+	PostingTransfer   = "transfer"
+	PostingPurchase   = "purchase"
+	PostingRefund     = "refund"
+	PostingAdjustment = "adjustment"
+	// PostingDeduction - списание монет администратором; начисления записываются как PostingGrant.
+	PostingDeduction = "deduction"
+)
+
+// Счета журнала. Счет пользователя задается LedgerEntry.UserID, остальные счета системные.
+const (
+	AccountUser = "user"
+	// AccountIssuance - выпуск монет: с него списываются начисления пользователям.
+	AccountIssuance = "issuance"
+	// AccountStore - выручка магазина: на него зачисляется оплата покупок.
+	AccountStore = "store"
+)
+
+// LedgerEntry - запись проводки. Отрицательная сумма списывает со счета, положительная зачисляет.
+type LedgerEntry struct {
+	Account string
+	UserID  *uuid.UUID
+	Amount  int
+}
+
+// UserEntry - запись по счету пользователя.
+func UserEntry(userID uuid.UUID, amount int) LedgerEntry {
+	return LedgerEntry{Account: AccountUser, UserID: &userID, Amount: amount}
+}
+
+// SystemEntry - запись по системному счету.
+func SystemEntry(account string, amount int) LedgerEntry {
+	return LedgerEntry{Account: account, Amount: amount}
+}
+
+// BalanceMismatch - пользователь, чей кешированный баланс расходится с журналом.
+type BalanceMismatch struct {
+	UserID   uuid.UUID `json:"userId"`
+	Username string    `json:"username"`
+	Cached   int       `json:"cached"`
+	Ledger   int       `json:"ledger"`
+}
+
+// LedgerCheck - результат сверки балансов с журналом.
+type LedgerCheck struct {
+	Consistent bool              `json:"consistent"`
+	Mismatches []BalanceMismatch `json:"mismatches"`
+	// UnbalancedPostings - проводки с ненулевой суммой записей.
+	UnbalancedPostings []uuid.UUID `json:"unbalancedPostings"`
+}
+
+// BalanceReconciliation - ожидаемый баланс пользователя, пересчитанный по истории, и фактический баланс.
+type BalanceReconciliation struct {
+	UserID   uuid.UUID `json:"userId"`
+	Username string    `json:"username"`
+	// Grants - начисления и списания из журнала, включая начальный баланс.
+	Grants    int `json:"grants"`
+	Received  int `json:"received"`
+	Sent      int `json:"sent"`
+	Purchases int `json:"purchases"`
+	Expected  int `json:"expected"`
+	Actual    int `json:"actual"`
+}
+
+// Difference - сколько монет не хватает на балансе (отрицательное значение - сколько лишних).
+func (r BalanceReconciliation) Difference() int {
+	return r.Expected - r.Actual
+}
+
+// ReconcileReport - результат сверки балансов с историей.
+type ReconcileReport struct {
+	Checked    int                     `json:"checked"`
+	Mismatches []BalanceReconciliation `json:"mismatches"`
+	// Fixed - для расхождений записаны корректирующие проводки.
+	Fixed bool `json:"fixed"`
+}
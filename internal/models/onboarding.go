@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// WelcomeBonus - правило приветственного бонуса, который начисляется при регистрации сверх начального баланса.
+type WelcomeBonus struct {
+	// Name - название правила, попадает в причину начисления в истории монет.
+	Name   string
+	Amount int
+	// From и Until ограничивают дату регистрации полуинтервалом [From, Until); нулевая граница не ограничивает.
+	From  time.Time
+	Until time.Time
+	// InviteCode - если задан, бонус получают только пользователи, зарегистрировавшиеся с этим кодом приглашения.
+	InviteCode string
+}
+
+// Applies сообщает, положен ли бонус пользователю, зарегистрированному в момент at с кодом inviteCode.
+func (b WelcomeBonus) Applies(at time.Time, inviteCode string) bool {
+	if !b.From.IsZero() && at.Before(b.From) {
+		return false
+	}
+	if !b.Until.IsZero() && !at.Before(b.Until) {
+		return false
+	}
+
+	return b.InviteCode == "" || b.InviteCode == inviteCode
+}
+
+// Reason - причина начисления бонуса в истории монет.
+func (b WelcomeBonus) Reason() string {
+	return "welcome bonus: " + b.Name
+}
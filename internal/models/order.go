@@ -0,0 +1,58 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы выдачи заказа.
+const (
+	OrderPlaced         = "placed"
+	OrderPacked         = "packed"
+	OrderReadyForPickup = "ready_for_pickup"
+	OrderDelivered      = "delivered"
+	OrderCancelled      = "cancelled"
+)
+
+// orderTransitions - допустимые переходы между статусами. Выданный и отмененный заказы конечны.
+var orderTransitions = map[string][]string{
+	OrderPlaced:         {OrderPacked, OrderCancelled},
+	OrderPacked:         {OrderReadyForPickup, OrderCancelled},
+	OrderReadyForPickup: {OrderDelivered, OrderCancelled},
+}
+
+// CanTransitionOrder сообщает, можно ли перевести заказ из статуса from в статус to.
+func CanTransitionOrder(from, to string) bool {
+	return slices.Contains(orderTransitions[from], to)
+}
+
+// IsValidOrderStatus сообщает, существует ли такой статус заказа.
+func IsValidOrderStatus(status string) bool {
+	switch status {
+	case OrderPlaced, OrderPacked, OrderReadyForPickup, OrderDelivered, OrderCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrderTransition - смена статуса заказа. У первой записи (создание заказа) From пустой.
+type OrderTransition struct {
+	From      string     `json:"from,omitempty"`
+	To        string     `json:"to"`
+	ActorID   *uuid.UUID `json:"actorId,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// FulfillmentOrder - заказ с точки зрения склада: что выдать, кому и на каком он этапе.
+type FulfillmentOrder struct {
+	ID          uuid.UUID         `json:"id"`
+	UserID      uuid.UUID         `json:"userId"`
+	Status      string            `json:"status"`
+	Items       []OrderLine       `json:"items"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+	Transitions []OrderTransition `json:"transitions,omitempty"`
+}
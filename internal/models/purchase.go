@@ -6,10 +6,48 @@ import (
 	"github.com/google/uuid"
 )
 
-//nolint:tagliatelle // snake_case is allowed here.
+// Статусы покупки.
+const (
+	PurchaseCompleted       = "completed"
+	PurchaseRefundRequested = "refund_requested"
+	PurchaseRefunded        = "refunded"
+)
+
+// Purchase - купленная единица товара.
+//
+//nolint:tagliatelle // user_id и created_at остаются в snake_case для совместимости с существующими клиентами.
 type Purchase struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	UserID    uuid.UUID `json:"user_id" db:"user_id"`
-	Item      string    `json:"item" db:"item"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID                uuid.UUID  `json:"id" db:"id"`
+	UserID            uuid.UUID  `json:"user_id" db:"user_id"`
+	OrderID           uuid.UUID  `json:"orderId" db:"order_id"`
+	Item              string     `json:"item" db:"item"`
+	Price             int        `json:"price" db:"price"`
+	Status            string     `json:"status" db:"status"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	RefundRequestedAt *time.Time `json:"refundRequestedAt,omitempty" db:"refund_requested_at"`
+	RefundedAt        *time.Time `json:"refundedAt,omitempty" db:"refunded_at"`
+}
+
+// OrderLine - товар и количество в заказе.
+type OrderLine struct {
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+}
+
+// Order - результат покупки: созданный заказ, по одной записи Purchase на каждую купленную единицу
+// и баланс после списания.
+type Order struct {
+	ID        uuid.UUID  `json:"id"`
+	Status    string     `json:"status"`
+	Purchases []Purchase `json:"purchases"`
+	Total     int        `json:"total"`
+	Balance   int        `json:"balance"`
+}
+
+// InventoryItem - количество купленных пользователем единиц товара. Statuses показывает, сколько
+// из них находится в каждом статусе выдачи заказа.
+type InventoryItem struct {
+	Type     string         `json:"type"`
+	Quantity int            `json:"quantity"`
+	Statuses map[string]int `json:"statuses,omitempty"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken - выданный пользователю refresh-токен. Сам токен не хранится, только его хеш.
+// Все токены, полученные ротацией из одного входа, имеют общий FamilyID.
+type RefreshToken struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"userId" db:"user_id"`
+	FamilyID  uuid.UUID  `json:"familyId" db:"family_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expiresAt" db:"expires_at"`
+	UsedAt    *time.Time `json:"usedAt" db:"used_at"`
+	RevokedAt *time.Time `json:"revokedAt" db:"revoked_at"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+}
@@ -14,3 +14,37 @@ type Transaction struct {
 	Amount    int       `json:"amount" db:"amount"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
+
+// ReceivedTransfer - входящий перевод с именем отправителя.
+type ReceivedTransfer struct {
+	FromUser string `json:"fromUser"`
+	Amount   int    `json:"amount"`
+}
+
+// SentTransfer - исходящий перевод с именем получателя.
+type SentTransfer struct {
+	ToUser string `json:"toUser"`
+	Amount int    `json:"amount"`
+}
+
+// Refund - монеты, возвращенные пользователю за покупку.
+type Refund struct {
+	Item   string `json:"item"`
+	Amount int    `json:"amount"`
+}
+
+// Grant - начисление (положительная сумма) или списание (отрицательная) монет вне переводов и покупок:
+// начальный баланс и операции администраторов.
+type Grant struct {
+	Amount int    `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+// CoinHistory - история переводов пользователя, разделенная по направлению, возвраты за покупки
+// и начисления.
+type CoinHistory struct {
+	Received []ReceivedTransfer `json:"received"`
+	Sent     []SentTransfer     `json:"sent"`
+	Refunds  []Refund           `json:"refunds"`
+	Grants   []Grant            `json:"grants"`
+}
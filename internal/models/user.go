@@ -2,9 +2,29 @@ package models
 
 import "github.com/google/uuid"
 
+// Роли пользователей.
+const (
+	RoleEmployee = "employee"
+	RoleAdmin    = "admin"
+	RoleAuditor  = "auditor"
+	// RoleWarehouse - сотрудник склада, который собирает и выдает заказы.
+	RoleWarehouse = "warehouse"
+)
+
 type User struct {
 	ID       uuid.UUID `json:"id" db:"id"`
 	Username string    `json:"username" db:"username"`
 	Password string    `json:"-" db:"password"`
 	Coins    int       `json:"coins" db:"coins"`
+	Role     string    `json:"role" db:"role"`
+}
+
+// IsValidRole сообщает, существует ли такая роль.
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleEmployee, RoleAdmin, RoleAuditor, RoleWarehouse:
+		return true
+	default:
+		return false
+	}
 }
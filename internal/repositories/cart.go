@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// AddToCart добавляет товар в корзину; если он там уже есть, увеличивает количество.
+// AddToCart добавляет единицы товара к строке корзины. Строка не может превысить services.MaxOrderQuantity,
+// иначе ее нельзя было бы оформить.
+func (s *Storage) AddToCart(ctx context.Context, userID uuid.UUID, itemName string, quantity int) error {
+	query := `INSERT INTO cart_items (user_id, item, quantity) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, item) DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity
+		WHERE cart_items.quantity + EXCLUDED.quantity <= $4`
+	result, err := s.conn(ctx).ExecContext(ctx, query, userID, itemName, quantity, services.MaxOrderQuantity)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return services.ErrQuantityTooLarge
+	}
+
+	return nil
+}
+
+func (s *Storage) RemoveFromCart(ctx context.Context, userID uuid.UUID, itemName string) error {
+	query := `DELETE FROM cart_items WHERE user_id = $1 AND item = $2`
+	result, err := s.conn(ctx).ExecContext(ctx, query, userID, itemName)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return services.ErrCartItemNotFound
+	}
+
+	return nil
+}
+
+// GetCart возвращает строки корзины с текущими ценами и доступностью товаров.
+func (s *Storage) GetCart(ctx context.Context, userID uuid.UUID) ([]models.CartLine, error) {
+	query := `SELECT c.item, c.quantity, i.price,
+			i.retired_at IS NULL AND (i.stock IS NULL OR i.stock >= c.quantity)
+		FROM cart_items c
+		JOIN items i ON i.name = c.item
+		WHERE c.user_id = $1
+		ORDER BY c.added_at, c.item`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make([]models.CartLine, 0)
+	for rows.Next() {
+		var line models.CartLine
+		if err := rows.Scan(&line.Item, &line.Quantity, &line.Price, &line.Available); err != nil {
+			return nil, err
+		}
+		line.Subtotal = line.Price * line.Quantity
+		lines = append(lines, line)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// ClearCart убирает из корзины купленные строки. Количество уменьшается на купленное, а не удаляется вся
+// корзина: товары, добавленные параллельно с оформлением заказа, остаются в ней.
+func (s *Storage) ClearCart(ctx context.Context, userID uuid.UUID, lines []models.OrderLine) error {
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		for _, line := range lines {
+			query := `DELETE FROM cart_items WHERE user_id = $1 AND item = $2 AND quantity <= $3`
+			if _, err := s.conn(ctx).ExecContext(ctx, query, userID, line.Item, line.Quantity); err != nil {
+				return err
+			}
+
+			query = `UPDATE cart_items SET quantity = quantity - $3 WHERE user_id = $1 AND item = $2`
+			if _, err := s.conn(ctx).ExecContext(ctx, query, userID, line.Item, line.Quantity); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_AddToCart(t *testing.T) {
+	tests := []struct {
+		name        string
+		affected    int64
+		expectedErr error
+	}{
+		{
+			name:     "line is added",
+			affected: 1,
+		},
+		{
+			name:        "line would exceed the limit",
+			affected:    0,
+			expectedErr: services.ErrQuantityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			userID := uuid.New()
+			mock.ExpectExec(`INSERT INTO cart_items`).
+				WithArgs(userID, "cup", 5, services.MaxOrderQuantity).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			s := &Storage{db: db}
+			err = s.AddToCart(context.Background(), userID, "cup", 5)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// GrantCoins начисляет пользователю amount монет (или списывает, если amount отрицательный) проводкой
+// от имени администратора и возвращает новый баланс. Списание не может сделать баланс отрицательным.
+func (s *Storage) GrantCoins(
+	ctx context.Context,
+	batchID, adminID, userID uuid.UUID,
+	amount int,
+	reason string,
+) (int, error) {
+	var balance int
+	err := s.WithinTx(ctx, func(ctx context.Context) error {
+		query := `SELECT coins FROM users WHERE id = $1 FOR UPDATE`
+		if err := s.conn(ctx).QueryRowContext(ctx, query, userID).Scan(&balance); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return services.ErrUserNotFound
+			}
+			return err
+		}
+
+		if balance+amount < 0 {
+			return services.ErrInsufficientCoins
+		}
+
+		kind := models.PostingGrant
+		if amount < 0 {
+			kind = models.PostingDeduction
+		}
+		if err := s.postBy(ctx, &adminID, kind, &batchID, reason,
+			models.SystemEntry(models.AccountIssuance, -amount),
+			models.UserEntry(userID, amount),
+		); err != nil {
+			return err
+		}
+
+		balance += amount
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+// GetGrantsByUserID возвращает начисления и списания пользователя для истории монет.
+func (s *Storage) GetGrantsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Grant, error) {
+	query := `SELECT e.amount, p.note
+		FROM ledger_entries e
+		JOIN ledger_postings p ON p.id = e.posting_id
+		WHERE e.user_id = $1 AND p.kind IN ('grant', 'deduction')
+		ORDER BY p.created_at`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grants := make([]models.Grant, 0)
+	for rows.Next() {
+		var grant models.Grant
+		if err = rows.Scan(&grant.Amount, &grant.Reason); err != nil {
+			return nil, err
+		}
+		grants = append(grants, grant)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return grants, nil
+}
+
+// GrantBonus начисляет пользователю бонус за счет выпуска монет, например приветственный при регистрации.
+func (s *Storage) GrantBonus(ctx context.Context, userID uuid.UUID, amount int, reason string) error {
+	return s.post(ctx, models.PostingGrant, &userID, reason,
+		models.SystemEntry(models.AccountIssuance, -amount),
+		models.UserEntry(userID, amount),
+	)
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// purgeBatchSize ограничивает число просроченных ключей, удаляемых за одно резервирование.
+const purgeBatchSize = 100
+
+// ReserveIdempotencyKey занимает ключ для нового запроса. Возвращает false, если по ключу уже сохранен
+// ответ и ключ еще не истек. Ключ занимается в транзакции запроса: пока она не завершена, повтор с тем же
+// ключом ждет ее окончания. Поэтому ключ без ответа может остаться только после старых версий сервиса
+// и занимается заново.
+func (s *Storage) ReserveIdempotencyKey(
+	ctx context.Context,
+	userID uuid.UUID,
+	key, requestHash string,
+	expiresAt time.Time,
+) (bool, error) {
+	// Ключи обычно уникальны и не переиспользуются, поэтому просроченные записи понемногу удаляются здесь же.
+	purge := `DELETE FROM idempotency_keys WHERE ctid IN (
+		SELECT ctid FROM idempotency_keys WHERE expires_at < CURRENT_TIMESTAMP LIMIT $1)`
+	if _, err := s.conn(ctx).ExecContext(ctx, purge, purgeBatchSize); err != nil {
+		return false, err
+	}
+
+	query := `INSERT INTO idempotency_keys (user_id, key, request_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			expires_at = EXCLUDED.expires_at,
+			status_code = NULL,
+			content_type = '',
+			response = NULL,
+			created_at = CURRENT_TIMESTAMP
+		WHERE idempotency_keys.expires_at < CURRENT_TIMESTAMP
+			OR idempotency_keys.status_code IS NULL
+		RETURNING true`
+
+	var reserved bool
+	err := s.conn(ctx).QueryRowContext(ctx, query, userID, key, requestHash, expiresAt).Scan(&reserved)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return reserved, nil
+}
+
+func (s *Storage) GetIdempotentResponse(ctx context.Context, userID uuid.UUID, key string) (*models.IdempotentResponse, error) {
+	query := `SELECT request_hash, COALESCE(status_code, 0), content_type, response
+		FROM idempotency_keys WHERE user_id = $1 AND key = $2`
+
+	var resp models.IdempotentResponse
+	err := s.conn(ctx).QueryRowContext(ctx, query, userID, key).
+		Scan(&resp.RequestHash, &resp.StatusCode, &resp.ContentType, &resp.Body)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// SaveIdempotentResponse сохраняет ответ на запрос. Ответ записывается в ключ, занятый этим же запросом
+// и еще не получивший ответа, либо в свободный ключ; если ключ занял другой запрос, возвращается
+// services.ErrIdempotencyKeyInProgress.
+func (s *Storage) SaveIdempotentResponse(
+	ctx context.Context,
+	userID uuid.UUID,
+	key string,
+	resp *models.IdempotentResponse,
+	expiresAt time.Time,
+) error {
+	query := `INSERT INTO idempotency_keys (user_id, key, request_hash, status_code, content_type, response, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, key) DO UPDATE SET
+			status_code = EXCLUDED.status_code,
+			content_type = EXCLUDED.content_type,
+			response = EXCLUDED.response
+		WHERE idempotency_keys.request_hash = EXCLUDED.request_hash AND idempotency_keys.status_code IS NULL`
+	result, err := s.conn(ctx).ExecContext(ctx, query,
+		userID, key, resp.RequestHash, resp.StatusCode, resp.ContentType, resp.Body, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return services.ErrIdempotencyKeyInProgress
+	}
+
+	return nil
+}
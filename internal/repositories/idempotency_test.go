@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_SaveIdempotentResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		affected    int64
+		expectedErr error
+	}{
+		{
+			name:     "response is saved",
+			affected: 1,
+		},
+		{
+			name:        "key is held by another request",
+			affected:    0,
+			expectedErr: services.ErrIdempotencyKeyInProgress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			userID := uuid.New()
+			expiresAt := time.Now().Add(time.Hour)
+			resp := &models.IdempotentResponse{
+				RequestHash: "hash",
+				StatusCode:  200,
+				ContentType: "application/json",
+				Body:        []byte(`{}`),
+			}
+
+			// Ответ записывается, только если ключ занят этим же запросом и ответа еще нет.
+			mock.ExpectExec(`WHERE idempotency_keys.request_hash = EXCLUDED.request_hash AND idempotency_keys.status_code IS NULL`).
+				WithArgs(userID, "key", "hash", 200, "application/json", []byte(`{}`), expiresAt).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			s := &Storage{db: db}
+			err = s.SaveIdempotentResponse(context.Background(), userID, "key", resp, expiresAt)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
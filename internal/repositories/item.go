@@ -4,13 +4,32 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/google/uuid"
 )
 
+const itemColumns = `name, price, retired_at, stock, per_user_limit`
+
+// scanner - общий интерфейс *sql.Row и *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanItem(row scanner) (models.Item, error) {
+	var item models.Item
+	err := row.Scan(&item.Name, &item.Price, &item.RetiredAt, &item.Stock, &item.PerUserLimit)
+	return item, err
+}
+
+// GetAllItems возвращает товары, которые сейчас продаются. Снятые с продажи товары не выводятся.
 func (s *Storage) GetAllItems(ctx context.Context) ([]models.Item, error) {
-	query := `SELECT name, price FROM items`
-	rows, err := s.db.QueryContext(ctx, query)
+	query := `SELECT ` + itemColumns + ` FROM items WHERE retired_at IS NULL`
+	rows, err := s.conn(ctx).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -18,8 +37,8 @@ func (s *Storage) GetAllItems(ctx context.Context) ([]models.Item, error) {
 
 	var items []models.Item
 	for rows.Next() {
-		var item models.Item
-		if err := rows.Scan(&item.Name, &item.Price); err != nil {
+		item, err := scanItem(rows)
+		if err != nil {
 			return nil, err
 		}
 		items = append(items, item)
@@ -33,11 +52,10 @@ func (s *Storage) GetAllItems(ctx context.Context) ([]models.Item, error) {
 }
 
 func (s *Storage) GetItemByName(ctx context.Context, name string) (*models.Item, error) {
-	query := `SELECT name, price FROM items WHERE name = $1`
-	row := s.db.QueryRowContext(ctx, query, name)
+	query := `SELECT ` + itemColumns + ` FROM items WHERE name = $1`
+	row := s.conn(ctx).QueryRowContext(ctx, query, name)
 
-	var item models.Item
-	err := row.Scan(&item.Name, &item.Price)
+	item, err := scanItem(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -46,3 +64,217 @@ func (s *Storage) GetItemByName(ctx context.Context, name string) (*models.Item,
 	}
 	return &item, nil
 }
+
+func (s *Storage) CreateItem(ctx context.Context, item *models.Item) error {
+	query := `INSERT INTO items (name, price) VALUES ($1, $2)`
+	_, err := s.conn(ctx).ExecContext(ctx, query, item.Name, item.Price)
+	if isUniqueViolation(err) {
+		return services.ErrItemExists
+	}
+	return err
+}
+
+func (s *Storage) UpdateItemPrice(ctx context.Context, name string, price int) error {
+	query := `UPDATE items SET price = $1 WHERE name = $2`
+	result, err := s.conn(ctx).ExecContext(ctx, query, price, name)
+	return itemAffected(result, err)
+}
+
+// RenameItem меняет название товара. Записи о покупках обновляются каскадно через внешний ключ.
+func (s *Storage) RenameItem(ctx context.Context, name, newName string) error {
+	query := `UPDATE items SET name = $1 WHERE name = $2`
+	result, err := s.conn(ctx).ExecContext(ctx, query, newName, name)
+	if isUniqueViolation(err) {
+		return services.ErrItemExists
+	}
+	return itemAffected(result, err)
+}
+
+// SetItemPurchaseLimit задает ограничение покупок на пользователя; nil снимает ограничение.
+func (s *Storage) SetItemPurchaseLimit(ctx context.Context, name string, limit *int) error {
+	query := `UPDATE items SET per_user_limit = $1 WHERE name = $2`
+	result, err := s.conn(ctx).ExecContext(ctx, query, limit, name)
+	return itemAffected(result, err)
+}
+
+// RestockItem увеличивает остаток товара и записывает движение в журнал. Товар без ограничения
+// количества после пополнения становится ограниченным.
+func (s *Storage) RestockItem(ctx context.Context, name string, quantity int, adminID uuid.UUID, note string) error {
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		query := `UPDATE items SET stock = COALESCE(stock, 0) + $1 WHERE name = $2`
+		result, err := s.conn(ctx).ExecContext(ctx, query, quantity, name)
+		if err := itemAffected(result, err); err != nil {
+			return err
+		}
+
+		return s.createStockMovement(ctx, &models.StockMovement{
+			ID:     uuid.New(),
+			Item:   name,
+			Delta:  quantity,
+			Kind:   models.StockMovementRestock,
+			UserID: &adminID,
+			Note:   note,
+		})
+	})
+}
+
+// SetItemStock задает остаток товара; stock = nil снимает ограничение количества. В журнал движений
+// записывается корректировка на разницу со старым остатком, неограниченный остаток считается нулевым.
+func (s *Storage) SetItemStock(ctx context.Context, name string, stock *int, adminID uuid.UUID, note string) error {
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		var current sql.NullInt64
+		query := `SELECT stock FROM items WHERE name = $1 FOR UPDATE`
+		if err := s.conn(ctx).QueryRowContext(ctx, query, name).Scan(&current); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return services.ErrItemNotFound
+			}
+			return err
+		}
+
+		query = `UPDATE items SET stock = $1 WHERE name = $2`
+		if _, err := s.conn(ctx).ExecContext(ctx, query, stock, name); err != nil {
+			return err
+		}
+
+		delta := -int(current.Int64)
+		if stock != nil {
+			delta += *stock
+		}
+
+		return s.createStockMovement(ctx, &models.StockMovement{
+			ID:     uuid.New(),
+			Item:   name,
+			Delta:  delta,
+			Kind:   models.StockMovementAdjustment,
+			UserID: &adminID,
+			Note:   note,
+		})
+	})
+}
+
+func (s *Storage) GetStockMovements(ctx context.Context, name string) ([]models.StockMovement, error) {
+	query := `SELECT id, item, delta, kind, user_id, purchase_id, note, created_at
+		FROM stock_movements WHERE item = $1 ORDER BY created_at, id`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []models.StockMovement
+	for rows.Next() {
+		var m models.StockMovement
+		err := rows.Scan(&m.ID, &m.Item, &m.Delta, &m.Kind, &m.UserID, &m.PurchaseID, &m.Note, &m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+func (s *Storage) createStockMovement(ctx context.Context, m *models.StockMovement) error {
+	query := `INSERT INTO stock_movements (id, item, delta, kind, user_id, purchase_id, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := s.conn(ctx).ExecContext(ctx, query, m.ID, m.Item, m.Delta, m.Kind, m.UserID, m.PurchaseID, m.Note)
+	return err
+}
+
+// RetireItem снимает товар с продажи. Повторный вызов не меняет дату снятия.
+func (s *Storage) RetireItem(ctx context.Context, name string) error {
+	query := `UPDATE items SET retired_at = COALESCE(retired_at, CURRENT_TIMESTAMP) WHERE name = $1`
+	result, err := s.conn(ctx).ExecContext(ctx, query, name)
+	return itemAffected(result, err)
+}
+
+// itemAffected превращает обновление без затронутых строк в ErrItemNotFound.
+func itemAffected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return services.ErrItemNotFound
+	}
+
+	return nil
+}
+
+// ListItems возвращает страницу каталога. Пагинация по ключу (значение сортировки, название),
+// поэтому страницы не съезжают при добавлении товаров, а запрос не сканирует пропущенные строки.
+func (s *Storage) ListItems(ctx context.Context, filter models.ItemFilter) ([]models.Item, error) {
+	var (
+		conds = []string{"retired_at IS NULL"}
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.MinPrice != nil {
+		conds = append(conds, "price >= "+arg(*filter.MinPrice))
+	}
+	if filter.MaxPrice != nil {
+		conds = append(conds, "price <= "+arg(*filter.MaxPrice))
+	}
+	if filter.Search != "" {
+		conds = append(conds, "name ILIKE "+arg("%"+escapeLike(filter.Search)+"%")+` ESCAPE '\'`)
+	}
+
+	op, direction := ">", "ASC"
+	if filter.Desc {
+		op, direction = "<", "DESC"
+	}
+
+	orderBy := "name " + direction
+	if filter.SortBy == models.ItemSortPrice {
+		orderBy = "price " + direction + ", " + orderBy
+	}
+
+	if filter.After != nil {
+		if filter.SortBy == models.ItemSortPrice {
+			conds = append(conds, fmt.Sprintf("(price, name) %s (%s, %s)", op, arg(filter.After.Price), arg(filter.After.Name)))
+		} else {
+			conds = append(conds, fmt.Sprintf("name %s %s", op, arg(filter.After.Name)))
+		}
+	}
+
+	query := `SELECT ` + itemColumns + ` FROM items WHERE ` + strings.Join(conds, " AND ") +
+		` ORDER BY ` + orderBy + ` LIMIT ` + arg(filter.Limit)
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.Item, 0, filter.Limit)
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// escapeLike экранирует спецсимволы LIKE, чтобы поиск шел по буквальной подстроке.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
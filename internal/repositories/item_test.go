@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_SetItemStock(t *testing.T) {
+	adminID := uuid.New()
+	thirty := 30
+	ten := 10
+
+	tests := []struct {
+		name          string
+		current       any
+		stock         *int
+		expectedDelta int
+	}{
+		{
+			name:          "unlimited item gets initial stock",
+			current:       nil,
+			stock:         &thirty,
+			expectedDelta: 30,
+		},
+		{
+			name:          "stock is corrected downward",
+			current:       30,
+			stock:         &ten,
+			expectedDelta: -20,
+		},
+		{
+			name:          "item becomes unlimited",
+			current:       10,
+			stock:         nil,
+			expectedDelta: -10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			var stock any
+			if tt.stock != nil {
+				stock = *tt.stock
+			}
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT stock FROM items WHERE name = \$1 FOR UPDATE`).
+				WithArgs("pink-hoody").
+				WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(tt.current))
+			mock.ExpectExec(`UPDATE items SET stock = \$1 WHERE name = \$2`).
+				WithArgs(stock, "pink-hoody").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(`INSERT INTO stock_movements`).
+				WithArgs(sqlmock.AnyArg(), "pink-hoody", tt.expectedDelta, models.StockMovementAdjustment,
+					adminID, nil, "inventory").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			s := &Storage{db: db}
+			err = s.SetItemStock(context.Background(), "pink-hoody", tt.stock, adminID, "inventory")
+
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestStorage_SetItemStock_UnknownItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT stock FROM items WHERE name = \$1 FOR UPDATE`).
+		WithArgs("cap").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	s := &Storage{db: db}
+	err = s.SetItemStock(context.Background(), "cap", nil, uuid.New(), "")
+
+	assert.Equal(t, services.ErrItemNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/derticom/merch-store/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var errUnbalancedPosting = errors.New("ledger posting is not balanced")
+
+// post записывает проводку в журнал и обновляет кешированные балансы затронутых пользователей.
+// Сумма записей должна быть нулевой: сколько списано с одних счетов, столько зачислено на другие.
+// Баланс users.coins меняется только здесь.
+func (s *Storage) post(
+	ctx context.Context,
+	kind string,
+	referenceID *uuid.UUID,
+	note string,
+	entries ...models.LedgerEntry,
+) error {
+	return s.postBy(ctx, nil, kind, referenceID, note, entries...)
+}
+
+// postBy записывает проводку, как post, с указанием пользователя, выполнившего операцию.
+func (s *Storage) postBy(
+	ctx context.Context,
+	actorID *uuid.UUID,
+	kind string,
+	referenceID *uuid.UUID,
+	note string,
+	entries ...models.LedgerEntry,
+) error {
+	var sum int
+	for _, entry := range entries {
+		sum += entry.Amount
+	}
+	if sum != 0 || len(entries) < 2 {
+		return errUnbalancedPosting
+	}
+
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		postingID := uuid.New()
+		query := `INSERT INTO ledger_postings (id, kind, reference_id, note, created_by) VALUES ($1, $2, $3, $4, $5)`
+		if _, err := s.conn(ctx).ExecContext(ctx, query, postingID, kind, referenceID, note, actorID); err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			query = `INSERT INTO ledger_entries (posting_id, account, user_id, amount) VALUES ($1, $2, $3, $4)`
+			_, err := s.conn(ctx).ExecContext(ctx, query, postingID, entry.Account, entry.UserID, entry.Amount)
+			if err != nil {
+				return err
+			}
+
+			if entry.UserID == nil {
+				continue
+			}
+			query = `UPDATE users SET coins = coins + $1 WHERE id = $2`
+			if _, err := s.conn(ctx).ExecContext(ctx, query, entry.Amount, *entry.UserID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// CheckLedger пересчитывает балансы пользователей по журналу и сравнивает их с users.coins,
+// а также ищет проводки с ненулевой суммой записей.
+func (s *Storage) CheckLedger(ctx context.Context) (*models.LedgerCheck, error) {
+	check := &models.LedgerCheck{
+		Mismatches:         make([]models.BalanceMismatch, 0),
+		UnbalancedPostings: make([]uuid.UUID, 0),
+	}
+
+	query := `SELECT u.id, u.username, u.coins, COALESCE(SUM(e.amount), 0)
+		FROM users u
+		LEFT JOIN ledger_entries e ON e.user_id = u.id
+		GROUP BY u.id
+		HAVING u.coins <> COALESCE(SUM(e.amount), 0)
+		ORDER BY u.username`
+	rows, err := s.conn(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mismatch models.BalanceMismatch
+		if err := rows.Scan(&mismatch.UserID, &mismatch.Username, &mismatch.Cached, &mismatch.Ledger); err != nil {
+			return nil, err
+		}
+		check.Mismatches = append(check.Mismatches, mismatch)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT posting_id FROM ledger_entries GROUP BY posting_id HAVING SUM(amount) <> 0`
+	unbalanced, err := s.conn(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer unbalanced.Close()
+
+	for unbalanced.Next() {
+		var id uuid.UUID
+		if err := unbalanced.Scan(&id); err != nil {
+			return nil, err
+		}
+		check.UnbalancedPostings = append(check.UnbalancedPostings, id)
+	}
+	if err = unbalanced.Err(); err != nil {
+		return nil, err
+	}
+
+	check.Consistent = len(check.Mismatches) == 0 && len(check.UnbalancedPostings) == 0
+
+	return check, nil
+}
@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	insertPostingQuery = `INSERT INTO ledger_postings (id, kind, reference_id, note, created_by) VALUES ($1, $2, $3, $4, $5)`
+	insertEntryQuery   = `INSERT INTO ledger_entries (posting_id, account, user_id, amount) VALUES ($1, $2, $3, $4)`
+	updateCoinsQuery   = `UPDATE users SET coins = coins + $1 WHERE id = $2`
+)
+
+func TestStorage_PostBy(t *testing.T) {
+	alice := uuid.New()
+	bob := uuid.New()
+	adminID := uuid.New()
+	referenceID := uuid.New()
+	dbErr := errors.New("connection reset")
+
+	tests := []struct {
+		name        string
+		actorID     *uuid.UUID
+		entries     []models.LedgerEntry
+		setup       func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name:    "transfer updates both cached balances",
+			entries: []models.LedgerEntry{models.UserEntry(alice, -30), models.UserEntry(bob, 30)},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(insertPostingQuery).
+					WithArgs(sqlmock.AnyArg(), models.PostingTransfer, referenceID, "note", nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(insertEntryQuery).
+					WithArgs(sqlmock.AnyArg(), models.AccountUser, alice, -30).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(updateCoinsQuery).WithArgs(-30, alice).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(insertEntryQuery).
+					WithArgs(sqlmock.AnyArg(), models.AccountUser, bob, 30).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(updateCoinsQuery).WithArgs(30, bob).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "system account entry does not touch users",
+			actorID: &adminID,
+			entries: []models.LedgerEntry{
+				models.SystemEntry(models.AccountIssuance, -100),
+				models.UserEntry(alice, 100),
+			},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(insertPostingQuery).
+					WithArgs(sqlmock.AnyArg(), models.PostingTransfer, referenceID, "note", adminID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(insertEntryQuery).
+					WithArgs(sqlmock.AnyArg(), models.AccountIssuance, nil, -100).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(insertEntryQuery).
+					WithArgs(sqlmock.AnyArg(), models.AccountUser, alice, 100).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(updateCoinsQuery).WithArgs(100, alice).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:        "unbalanced posting is rejected before writing",
+			entries:     []models.LedgerEntry{models.UserEntry(alice, -30), models.UserEntry(bob, 20)},
+			setup:       func(sqlmock.Sqlmock) {},
+			expectedErr: errUnbalancedPosting,
+		},
+		{
+			name:        "single entry is rejected",
+			entries:     []models.LedgerEntry{{Account: models.AccountStore}},
+			setup:       func(sqlmock.Sqlmock) {},
+			expectedErr: errUnbalancedPosting,
+		},
+		{
+			name:    "failed cache update rolls back the posting",
+			entries: []models.LedgerEntry{models.UserEntry(alice, -30), models.UserEntry(bob, 30)},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(insertPostingQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(insertEntryQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(updateCoinsQuery).WithArgs(-30, alice).WillReturnError(dbErr)
+				mock.ExpectRollback()
+			},
+			expectedErr: dbErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.setup(mock)
+
+			s := &Storage{db: db}
+			err = s.postBy(context.Background(), tt.actorID, models.PostingTransfer, &referenceID, "note", tt.entries...)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestStorage_CheckLedger(t *testing.T) {
+	aliceID := uuid.New()
+	postingID := uuid.New()
+
+	tests := []struct {
+		name       string
+		mismatches *sqlmock.Rows
+		unbalanced *sqlmock.Rows
+		expected   *models.LedgerCheck
+	}{
+		{
+			name:       "consistent ledger",
+			mismatches: sqlmock.NewRows([]string{"id", "username", "coins", "sum"}),
+			unbalanced: sqlmock.NewRows([]string{"posting_id"}),
+			expected: &models.LedgerCheck{
+				Consistent:         true,
+				Mismatches:         []models.BalanceMismatch{},
+				UnbalancedPostings: []uuid.UUID{},
+			},
+		},
+		{
+			name: "mismatches and unbalanced postings are reported",
+			mismatches: sqlmock.NewRows([]string{"id", "username", "coins", "sum"}).
+				AddRow(aliceID, "alice", 900, 950),
+			unbalanced: sqlmock.NewRows([]string{"posting_id"}).AddRow(postingID),
+			expected: &models.LedgerCheck{
+				Mismatches:         []models.BalanceMismatch{{UserID: aliceID, Username: "alice", Cached: 900, Ledger: 950}},
+				UnbalancedPostings: []uuid.UUID{postingID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(`HAVING u.coins <> COALESCE\(SUM\(e.amount\), 0\)`).WillReturnRows(tt.mismatches)
+			mock.ExpectQuery(`SELECT posting_id FROM ledger_entries GROUP BY posting_id HAVING SUM\(amount\) <> 0`).
+				WillReturnRows(tt.unbalanced)
+
+			s := &Storage{db: db}
+			check, err := s.CheckLedger(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, check)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
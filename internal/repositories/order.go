@@ -0,0 +1,208 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/derticom/merch-store/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// createOrder создает заказ в статусе placed. Автор первого перехода - покупатель.
+func (s *Storage) createOrder(ctx context.Context, id, userID uuid.UUID) error {
+	query := `INSERT INTO orders (id, user_id, status) VALUES ($1, $2, $3)`
+	if _, err := s.conn(ctx).ExecContext(ctx, query, id, userID, models.OrderPlaced); err != nil {
+		return err
+	}
+
+	return s.createOrderTransition(ctx, id, "", models.OrderPlaced, &userID)
+}
+
+func (s *Storage) createOrderTransition(ctx context.Context, orderID uuid.UUID, from, to string, actorID *uuid.UUID) error {
+	query := `INSERT INTO order_transitions (order_id, from_status, to_status, actor_id)
+		VALUES ($1, NULLIF($2, ''), $3, $4)`
+	_, err := s.conn(ctx).ExecContext(ctx, query, orderID, from, to, actorID)
+	return err
+}
+
+// GetOrderByID возвращает заказ с товарами и журналом переходов и блокирует его до конца транзакции.
+// Если заказа нет, возвращает nil, nil.
+func (s *Storage) GetOrderByID(ctx context.Context, id uuid.UUID) (*models.FulfillmentOrder, error) {
+	query := `SELECT id, user_id, status, created_at, updated_at FROM orders WHERE id = $1 FOR UPDATE`
+
+	var order models.FulfillmentOrder
+	err := s.conn(ctx).QueryRowContext(ctx, query, id).
+		Scan(&order.ID, &order.UserID, &order.Status, &order.CreatedAt, &order.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	order.Items, err = s.getOrderItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	order.Transitions, err = s.getOrderTransitions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// getOrderItems возвращает товары заказа, которые нужно собрать и выдать; возвращенные единицы не учитываются.
+func (s *Storage) getOrderItems(ctx context.Context, orderID uuid.UUID) ([]models.OrderLine, error) {
+	query := `SELECT item, COUNT(*) FROM purchase WHERE order_id = $1 AND status <> 'refunded'
+		GROUP BY item ORDER BY item`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make([]models.OrderLine, 0)
+	for rows.Next() {
+		var line models.OrderLine
+		if err := rows.Scan(&line.Item, &line.Quantity); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+func (s *Storage) getOrderTransitions(ctx context.Context, orderID uuid.UUID) ([]models.OrderTransition, error) {
+	query := `SELECT COALESCE(from_status, ''), to_status, actor_id, created_at
+		FROM order_transitions WHERE order_id = $1 ORDER BY created_at`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transitions []models.OrderTransition
+	for rows.Next() {
+		var transition models.OrderTransition
+		if err := rows.Scan(&transition.From, &transition.To, &transition.ActorID, &transition.CreatedAt); err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, transition)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transitions, nil
+}
+
+// UpdateOrderStatus переводит заказ в статус to и записывает переход в журнал.
+func (s *Storage) UpdateOrderStatus(
+	ctx context.Context,
+	order *models.FulfillmentOrder,
+	to string,
+	actorID uuid.UUID,
+) error {
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		query := `UPDATE orders SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 RETURNING updated_at`
+		if err := s.conn(ctx).QueryRowContext(ctx, query, to, order.ID).Scan(&order.UpdatedAt); err != nil {
+			return err
+		}
+
+		if err := s.createOrderTransition(ctx, order.ID, order.Status, to, &actorID); err != nil {
+			return err
+		}
+
+		order.Transitions = append(order.Transitions, models.OrderTransition{
+			From:      order.Status,
+			To:        to,
+			ActorID:   &actorID,
+			CreatedAt: order.UpdatedAt,
+		})
+		order.Status = to
+
+		return nil
+	})
+}
+
+// GetPurchasesByOrderID возвращает покупки заказа и блокирует их до конца транзакции.
+func (s *Storage) GetPurchasesByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.Purchase, error) {
+	query := `SELECT ` + purchaseColumns + ` FROM purchase WHERE order_id = $1 ORDER BY created_at FOR UPDATE`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	purchases := make([]models.Purchase, 0)
+	for rows.Next() {
+		purchase, err := scanPurchase(rows)
+		if err != nil {
+			return nil, err
+		}
+		purchases = append(purchases, purchase)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return purchases, nil
+}
+
+// ListOrders возвращает заказы в указанном статусе, начиная с самых старых; пустой статус - все заказы.
+// Возвращенные единицы в товары заказа не входят, полностью возвращенный заказ выводится без товаров.
+func (s *Storage) ListOrders(ctx context.Context, status string) ([]models.FulfillmentOrder, error) {
+	query := `SELECT o.id, o.user_id, o.status, o.created_at, o.updated_at, p.item, COUNT(p.id)
+		FROM orders o
+		LEFT JOIN purchase p ON p.order_id = o.id AND p.status <> 'refunded'
+		WHERE $1 = '' OR o.status = $1
+		GROUP BY o.id, p.item
+		ORDER BY o.created_at, o.id, p.item`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.FulfillmentOrder, 0)
+	for rows.Next() {
+		var (
+			order models.FulfillmentOrder
+			item  sql.NullString
+			line  models.OrderLine
+		)
+		err := rows.Scan(&order.ID, &order.UserID, &order.Status, &order.CreatedAt, &order.UpdatedAt,
+			&item, &line.Quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		// Строки одного заказа идут подряд, по одной на товар.
+		if n := len(orders); n == 0 || orders[n-1].ID != order.ID {
+			order.Items = make([]models.OrderLine, 0)
+			orders = append(orders, order)
+		}
+		if item.Valid {
+			last := &orders[len(orders)-1]
+			line.Item = item.String
+			last.Items = append(last.Items, line)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
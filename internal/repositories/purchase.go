@@ -2,30 +2,57 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"slices"
+	"strings"
 
 	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
 
 	"github.com/google/uuid"
 )
 
+const purchaseColumns = `id, user_id, order_id, item, price, status, created_at, refund_requested_at, refunded_at`
+
+func scanPurchase(row scanner) (models.Purchase, error) {
+	var purchase models.Purchase
+	err := row.Scan(
+		&purchase.ID,
+		&purchase.UserID,
+		&purchase.OrderID,
+		&purchase.Item,
+		&purchase.Price,
+		&purchase.Status,
+		&purchase.CreatedAt,
+		&purchase.RefundRequestedAt,
+		&purchase.RefundedAt,
+	)
+	return purchase, err
+}
+
 func (s *Storage) CreatePurchase(ctx context.Context, purchase *models.Purchase) error {
-	query := `INSERT INTO purchase (id, user_id, item) VALUES ($1, $2, $3)`
-	_, err := s.db.ExecContext(ctx, query, purchase.ID, purchase.UserID, purchase.Item)
+	query := `INSERT INTO purchase (id, user_id, order_id, item, price) VALUES ($1, $2, $3, $4, $5)
+		RETURNING status, created_at`
+	err := s.conn(ctx).QueryRowContext(ctx, query,
+		purchase.ID, purchase.UserID, purchase.OrderID, purchase.Item, purchase.Price,
+	).Scan(&purchase.Status, &purchase.CreatedAt)
 	return err
 }
 
 func (s *Storage) GetPurchasesByUserID(ctx context.Context, userID uuid.UUID) ([]models.Purchase, error) {
-	query := `SELECT id, user_id, item, created_at FROM purchase WHERE user_id = $1`
-	rows, err := s.db.QueryContext(ctx, query, userID)
+	query := `SELECT ` + purchaseColumns + ` FROM purchase WHERE user_id = $1 ORDER BY created_at`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var purchases []models.Purchase
+	purchases := make([]models.Purchase, 0)
 	for rows.Next() {
-		var purchase models.Purchase
-		if err := rows.Scan(&purchase.ID, &purchase.UserID, &purchase.Item, &purchase.CreatedAt); err != nil {
+		purchase, err := scanPurchase(rows)
+		if err != nil {
 			return nil, err
 		}
 		purchases = append(purchases, purchase)
@@ -37,3 +64,207 @@ func (s *Storage) GetPurchasesByUserID(ctx context.Context, userID uuid.UUID) ([
 
 	return purchases, nil
 }
+
+// GetInventoryByUserID возвращает купленные пользователем товары, сгруппированные по названию,
+// с разбивкой по статусам заказов. Возвращенные покупки в инвентарь не входят.
+func (s *Storage) GetInventoryByUserID(ctx context.Context, userID uuid.UUID) ([]models.InventoryItem, error) {
+	query := `SELECT p.item, o.status, COUNT(*)
+		FROM purchase p
+		JOIN orders o ON o.id = p.order_id
+		WHERE p.user_id = $1 AND p.status <> 'refunded'
+		GROUP BY p.item, o.status
+		ORDER BY p.item`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inventory := make([]models.InventoryItem, 0)
+	for rows.Next() {
+		var (
+			item     string
+			status   string
+			quantity int
+		)
+		if err := rows.Scan(&item, &status, &quantity); err != nil {
+			return nil, err
+		}
+
+		// Строки отсортированы по товару, поэтому статусы одного товара идут подряд.
+		if n := len(inventory); n == 0 || inventory[n-1].Type != item {
+			inventory = append(inventory, models.InventoryItem{Type: item, Statuses: make(map[string]int)})
+		}
+		last := &inventory[len(inventory)-1]
+		last.Quantity += quantity
+		last.Statuses[status] += quantity
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return inventory, nil
+}
+
+// BuyItem покупает одну единицу товара.
+func (s *Storage) BuyItem(ctx context.Context, userID uuid.UUID, itemName string) error {
+	_, err := s.BuyItems(ctx, userID, []models.OrderLine{{Item: itemName, Quantity: 1}})
+	return err
+}
+
+// BuyItems списывает стоимость всех строк заказа с баланса пользователя, уменьшает остатки и создает
+// заказ с записями о покупках в одной транзакции. Если хотя бы одну строку купить нельзя, не покупается ничего.
+func (s *Storage) BuyItems(ctx context.Context, userID uuid.UUID, lines []models.OrderLine) (*models.Order, error) {
+	// Строки товаров блокируются при уменьшении остатка; единый порядок исключает взаимные блокировки.
+	lines = slices.Clone(lines)
+	slices.SortFunc(lines, func(a, b models.OrderLine) int { return strings.Compare(a.Item, b.Item) })
+
+	var order *models.Order
+	err := s.WithinTx(ctx, func(ctx context.Context) error {
+		order = &models.Order{}
+
+		// Блокируем строку пользователя до конца транзакции, чтобы параллельные покупки выполнялись последовательно.
+		var coins int
+		query := `SELECT coins FROM users WHERE id = $1 FOR UPDATE`
+		err := s.conn(ctx).QueryRowContext(ctx, query, userID).Scan(&coins)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return services.ErrUserNotFound
+			}
+			return err
+		}
+
+		limited := make([]bool, len(lines))
+		prices := make([]int, len(lines))
+		for i, line := range lines {
+			var (
+				retired      bool
+				stock        sql.NullInt64
+				perUserLimit sql.NullInt64
+			)
+			query = `SELECT price, retired_at IS NOT NULL, stock, per_user_limit FROM items WHERE name = $1`
+			err = s.conn(ctx).QueryRowContext(ctx, query, line.Item).Scan(&prices[i], &retired, &stock, &perUserLimit)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return services.ErrItemNotFound
+				}
+				return err
+			}
+			if retired {
+				return services.ErrItemRetired
+			}
+			if line.Quantity > services.MaxOrderQuantity {
+				return services.ErrQuantityTooLarge
+			}
+			if stock.Valid && stock.Int64 < int64(line.Quantity) {
+				return services.ErrOutOfStock
+			}
+			limited[i] = stock.Valid
+
+			// Покупки одного пользователя сериализованы блокировкой его строки, поэтому подсчет не устареет.
+			if perUserLimit.Valid {
+				var bought int64
+				query = `SELECT COUNT(*) FROM purchase WHERE user_id = $1 AND item = $2 AND status <> 'refunded'`
+				if err := s.conn(ctx).QueryRowContext(ctx, query, userID, line.Item).Scan(&bought); err != nil {
+					return err
+				}
+				if bought+int64(line.Quantity) > perUserLimit.Int64 {
+					return services.ErrPurchaseLimitReached
+				}
+			}
+
+			// Суммы проводок хранятся в INT, поэтому итог проверяется до записи в журнал.
+			subtotal := int64(prices[i]) * int64(line.Quantity)
+			if int64(order.Total)+subtotal > math.MaxInt32 {
+				return services.ErrOrderTotalTooLarge
+			}
+			order.Total += int(subtotal)
+		}
+
+		if coins < order.Total {
+			return services.ErrInsufficientCoins
+		}
+
+		order.ID = uuid.New()
+		order.Status = models.OrderPlaced
+		if err := s.createOrder(ctx, order.ID, userID); err != nil {
+			return err
+		}
+
+		if order.Total > 0 {
+			err := s.post(ctx, models.PostingPurchase, &order.ID, "",
+				models.UserEntry(userID, -order.Total),
+				models.SystemEntry(models.AccountStore, order.Total),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		order.Balance = coins - order.Total
+
+		for i, line := range lines {
+			if limited[i] {
+				if err := s.decrementStock(ctx, line.Item, line.Quantity); err != nil {
+					return err
+				}
+			}
+
+			for range line.Quantity {
+				purchase := models.Purchase{
+					ID:      uuid.New(),
+					UserID:  userID,
+					OrderID: order.ID,
+					Item:    line.Item,
+					Price:   prices[i],
+				}
+				if err := s.CreatePurchase(ctx, &purchase); err != nil {
+					return err
+				}
+				order.Purchases = append(order.Purchases, purchase)
+
+				if !limited[i] {
+					continue
+				}
+				err := s.createStockMovement(ctx, &models.StockMovement{
+					ID:         uuid.New(),
+					Item:       line.Item,
+					Delta:      -1,
+					Kind:       models.StockMovementPurchase,
+					UserID:     &userID,
+					PurchaseID: &purchase.ID,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// decrementStock уменьшает остаток. Условие в UPDATE атомарно: при параллельных покупках последних
+// единиц строка блокируется, и вторая транзакция увидит уже уменьшенный остаток.
+func (s *Storage) decrementStock(ctx context.Context, itemName string, quantity int) error {
+	query := `UPDATE items SET stock = stock - $1 WHERE name = $2 AND stock >= $1`
+	result, err := s.conn(ctx).ExecContext(ctx, query, quantity, itemName)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return services.ErrOutOfStock
+	}
+
+	return nil
+}
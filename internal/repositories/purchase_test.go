@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_BuyItems_Limits(t *testing.T) {
+	tests := []struct {
+		name        string
+		price       int
+		quantity    int
+		expectedErr error
+	}{
+		{
+			name:        "quantity above limit",
+			price:       10,
+			quantity:    services.MaxOrderQuantity + 1,
+			expectedErr: services.ErrQuantityTooLarge,
+		},
+		{
+			name:        "total overflows ledger amount",
+			price:       math.MaxInt32 / 2,
+			quantity:    3,
+			expectedErr: services.ErrOrderTotalTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			userID := uuid.New()
+
+			// Заказ отклоняется до создания заказа и проводки в журнале.
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT coins FROM users WHERE id = \$1 FOR UPDATE`).
+				WithArgs(userID).
+				WillReturnRows(sqlmock.NewRows([]string{"coins"}).AddRow(1000))
+			mock.ExpectQuery(`SELECT price, retired_at IS NOT NULL, stock, per_user_limit FROM items`).
+				WithArgs("cup").
+				WillReturnRows(sqlmock.NewRows([]string{"price", "retired", "stock", "per_user_limit"}).
+					AddRow(tt.price, false, nil, nil))
+			mock.ExpectRollback()
+
+			s := &Storage{db: db}
+			order, err := s.BuyItems(context.Background(), userID, []models.OrderLine{{Item: "cup", Quantity: tt.quantity}})
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Nil(t, order)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
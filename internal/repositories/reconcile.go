@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// reconcileQuery собирает для пользователей исходные данные сверки: начисления и списания из журнала, переводы
+// и покупки, кроме возвращенных. Корректировки не учитываются: именно ими исправляются расхождения.
+const reconcileQuery = `SELECT u.id, u.username, u.coins,
+	COALESCE((SELECT SUM(e.amount) FROM ledger_entries e JOIN ledger_postings p ON p.id = e.posting_id
+		WHERE e.user_id = u.id AND p.kind IN ('grant', 'deduction')), 0),
+	COALESCE((SELECT SUM(amount) FROM transactions WHERE to_user = u.id), 0),
+	COALESCE((SELECT SUM(amount) FROM transactions WHERE from_user = u.id), 0),
+	COALESCE((SELECT SUM(price) FROM purchase WHERE user_id = u.id AND status <> 'refunded'), 0)
+	FROM users u`
+
+func scanReconciliation(row scanner) (models.BalanceReconciliation, error) {
+	var r models.BalanceReconciliation
+	err := row.Scan(&r.UserID, &r.Username, &r.Actual, &r.Grants, &r.Received, &r.Sent, &r.Purchases)
+	r.Expected = r.Grants + r.Received - r.Sent - r.Purchases
+	return r, err
+}
+
+// GetBalanceReconciliations пересчитывает ожидаемые балансы всех пользователей.
+func (s *Storage) GetBalanceReconciliations(ctx context.Context) ([]models.BalanceReconciliation, error) {
+	rows, err := s.conn(ctx).QueryContext(ctx, reconcileQuery+` ORDER BY u.username`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.BalanceReconciliation
+	for rows.Next() {
+		r, err := scanReconciliation(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetBalanceReconciliation пересчитывает ожидаемый баланс пользователя и блокирует его строку до конца транзакции.
+func (s *Storage) GetBalanceReconciliation(ctx context.Context, userID uuid.UUID) (*models.BalanceReconciliation, error) {
+	r, err := scanReconciliation(s.conn(ctx).QueryRowContext(ctx, reconcileQuery+` WHERE u.id = $1 FOR UPDATE OF u`, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, services.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// AdjustUserBalance записывает корректирующую проводку: amount зачисляется пользователю за счет выпуска монет
+// (или списывается, если отрицательный).
+func (s *Storage) AdjustUserBalance(ctx context.Context, userID uuid.UUID, amount int, note string) error {
+	return s.post(ctx, models.PostingAdjustment, &userID, note,
+		models.SystemEntry(models.AccountIssuance, -amount),
+		models.UserEntry(userID, amount),
+	)
+}
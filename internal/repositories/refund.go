@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/derticom/merch-store/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// GetPurchaseByID возвращает покупку и блокирует ее до конца транзакции. Если покупки нет, возвращает nil, nil.
+func (s *Storage) GetPurchaseByID(ctx context.Context, id uuid.UUID) (*models.Purchase, error) {
+	query := `SELECT ` + purchaseColumns + ` FROM purchase WHERE id = $1 FOR UPDATE`
+	purchase, err := scanPurchase(s.conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &purchase, nil
+}
+
+// RequestRefund переводит покупку в статус refund_requested.
+func (s *Storage) RequestRefund(ctx context.Context, purchase *models.Purchase) error {
+	query := `UPDATE purchase SET status = 'refund_requested', refund_requested_at = CURRENT_TIMESTAMP
+		WHERE id = $1 RETURNING status, refund_requested_at`
+	return s.conn(ctx).QueryRowContext(ctx, query, purchase.ID).Scan(&purchase.Status, &purchase.RefundRequestedAt)
+}
+
+// RefundPurchase возвращает пользователю цену покупки, а товар - в остаток, если количество товара
+// ограничено, и переводит покупку в статус refunded. Все изменения выполняются в одной транзакции.
+func (s *Storage) RefundPurchase(ctx context.Context, purchase *models.Purchase) error {
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		if purchase.Price > 0 {
+			err := s.post(ctx, models.PostingRefund, &purchase.ID, "",
+				models.SystemEntry(models.AccountStore, -purchase.Price),
+				models.UserEntry(purchase.UserID, purchase.Price),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		query := `UPDATE items SET stock = stock + 1 WHERE name = $1 AND stock IS NOT NULL`
+		result, err := s.conn(ctx).ExecContext(ctx, query, purchase.Item)
+		if err != nil {
+			return err
+		}
+		limited, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if limited > 0 {
+			err := s.createStockMovement(ctx, &models.StockMovement{
+				ID:         uuid.New(),
+				Item:       purchase.Item,
+				Delta:      1,
+				Kind:       models.StockMovementRefund,
+				UserID:     &purchase.UserID,
+				PurchaseID: &purchase.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		query = `UPDATE purchase SET status = 'refunded', refunded_at = CURRENT_TIMESTAMP
+			WHERE id = $1 RETURNING status, refunded_at`
+		return s.conn(ctx).QueryRowContext(ctx, query, purchase.ID).Scan(&purchase.Status, &purchase.RefundedAt)
+	})
+}
+
+// GetRefundRequests возвращает покупки, ожидающие одобрения возврата, в порядке поступления заявок.
+func (s *Storage) GetRefundRequests(ctx context.Context) ([]models.Purchase, error) {
+	query := `SELECT ` + purchaseColumns + ` FROM purchase WHERE status = 'refund_requested'
+		ORDER BY refund_requested_at`
+	rows, err := s.conn(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	purchases := make([]models.Purchase, 0)
+	for rows.Next() {
+		purchase, err := scanPurchase(rows)
+		if err != nil {
+			return nil, err
+		}
+		purchases = append(purchases, purchase)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return purchases, nil
+}
+
+// GetRefundsByUserID возвращает возвраты пользователя для истории монет.
+func (s *Storage) GetRefundsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Refund, error) {
+	query := `SELECT item, price FROM purchase WHERE user_id = $1 AND status = 'refunded' ORDER BY refunded_at`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunds := make([]models.Refund, 0)
+	for rows.Next() {
+		var refund models.Refund
+		if err = rows.Scan(&refund.Item, &refund.Amount); err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
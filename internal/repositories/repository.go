@@ -3,14 +3,39 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
+	"github.com/derticom/merch-store/internal/tracing"
+
+	"github.com/jackc/pgx"
 	_ "github.com/jackc/pgx/stdlib" //revive:disable:blank-imports // import for side effect is necessary here.
 	"github.com/pressly/goose/v3"
 )
 
+const (
+	// maxTxAttempts - максимальное число попыток выполнения транзакции при ошибках сериализации.
+	maxTxAttempts = 3
+
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+	sqlStateUniqueViolation      = "23505"
+)
+
+// dbtx - общий набор методов *sql.DB и *sql.Tx, через который выполняются запросы.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// txKey - ключ контекста, под которым хранится открытая транзакция.
+type txKey struct{}
+
 type Storage struct {
 	db *sql.DB
+	// schemaVersion - версия последней миграции из каталога, переданного в Migrate.
+	schemaVersion int64
 }
 
 func New(ctx context.Context, dsn string) (*Storage, error) {
@@ -44,5 +69,108 @@ func (s *Storage) Migrate(migrate string) (err error) {
 		return fmt.Errorf("failed to goose.Up: %w", err)
 	}
 
+	migrations, err := goose.CollectMigrations(migrate, 0, goose.MaxVersion)
+	if err != nil {
+		return fmt.Errorf("failed to goose.CollectMigrations: %w", err)
+	}
+
+	last, err := migrations.Last()
+	if err != nil {
+		return fmt.Errorf("failed to get last migration: %w", err)
+	}
+	s.schemaVersion = last.Version
+
+	return nil
+}
+
+// Stats возвращает статистику пула соединений с базой данных.
+func (s *Storage) Stats() sql.DBStats {
+	return s.db.Stats()
+}
+
+// Ping проверяет доступность базы данных.
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// CheckMigrations проверяет, что в базе применены все миграции, известные сервису.
+func (s *Storage) CheckMigrations(ctx context.Context) error {
+	if s.schemaVersion == 0 {
+		return errors.New("migrations have not been run")
+	}
+
+	version, err := goose.GetDBVersionContext(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("failed to goose.GetDBVersionContext: %w", err)
+	}
+
+	if version < s.schemaVersion {
+		return fmt.Errorf("database schema version %d is behind expected %d", version, s.schemaVersion)
+	}
+
 	return nil
 }
+
+// WithinTx выполняет fn в транзакции. Все методы Storage, вызванные с переданным в fn контекстом,
+// выполняются в этой транзакции. Если fn возвращает ошибку, транзакция откатывается. При ошибках
+// сериализации и взаимоблокировках транзакция повторяется целиком, поэтому fn не должна иметь
+// побочных эффектов вне базы данных. Вложенные вызовы выполняются в уже открытой транзакции.
+func (s *Storage) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	ctx, span := tracing.Start(ctx, "db.transaction")
+	defer tracing.End(span, &err)
+
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = s.runTx(ctx, fn)
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("transaction failed after %d attempts: %w", maxTxAttempts, err)
+}
+
+func (s *Storage) runTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// conn возвращает транзакцию из контекста, если она открыта, иначе пул соединений.
+func (s *Storage) conn(ctx context.Context) tracedConn {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tracedConn{conn: tx}
+	}
+
+	return tracedConn{conn: s.db}
+}
+
+func isRetryable(err error) bool {
+	code := sqlState(err)
+	return code == sqlStateSerializationFailure || code == sqlStateDeadlockDetected
+}
+
+func isUniqueViolation(err error) bool {
+	return sqlState(err) == sqlStateUniqueViolation
+}
+
+// sqlState возвращает код ошибки Postgres или пустую строку, если err не является ошибкой Postgres.
+func sqlState(err error) string {
+	var pgErr pgx.PgError
+	if !errors.As(err, &pgErr) {
+		return ""
+	}
+
+	return pgErr.Code
+}
@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testQuery = "UPDATE users SET coins = coins + 1"
+
+func TestStorage_WithinTx(t *testing.T) {
+	serializationFailure := pgx.PgError{Code: sqlStateSerializationFailure}
+	deadlock := pgx.PgError{Code: sqlStateDeadlockDetected}
+	uniqueViolation := pgx.PgError{Code: sqlStateUniqueViolation}
+	businessErr := errors.New("business error")
+
+	// attempt описывает одну попытку транзакции: результат запроса внутри fn и ожидаемое завершение.
+	type attempt struct {
+		execErr error
+		commit  bool
+	}
+
+	tests := []struct {
+		name          string
+		attempts      []attempt
+		fnErr         error
+		expectedCalls int
+		expectedErr   error
+	}{
+		{
+			name:          "commit on success",
+			attempts:      []attempt{{commit: true}},
+			expectedCalls: 1,
+		},
+		{
+			name:          "rollback when fn fails",
+			attempts:      []attempt{{}},
+			fnErr:         businessErr,
+			expectedCalls: 1,
+			expectedErr:   businessErr,
+		},
+		{
+			name:          "retry on serialization failure",
+			attempts:      []attempt{{execErr: serializationFailure}, {commit: true}},
+			expectedCalls: 2,
+		},
+		{
+			name:          "retry on deadlock",
+			attempts:      []attempt{{execErr: deadlock}, {commit: true}},
+			expectedCalls: 2,
+		},
+		{
+			name:          "no retry on other database errors",
+			attempts:      []attempt{{execErr: uniqueViolation}},
+			expectedCalls: 1,
+			expectedErr:   uniqueViolation,
+		},
+		{
+			name: "give up after max attempts",
+			attempts: []attempt{
+				{execErr: serializationFailure},
+				{execErr: serializationFailure},
+				{execErr: serializationFailure},
+			},
+			expectedCalls: maxTxAttempts,
+			expectedErr:   serializationFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+
+			for _, a := range tt.attempts {
+				mock.ExpectBegin()
+				exec := mock.ExpectExec(testQuery)
+				if a.execErr != nil {
+					exec.WillReturnError(a.execErr)
+				} else {
+					exec.WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				if a.commit {
+					mock.ExpectCommit()
+				} else {
+					mock.ExpectRollback()
+				}
+			}
+
+			s := &Storage{db: db}
+			calls := 0
+			err = s.WithinTx(context.Background(), func(ctx context.Context) error {
+				calls++
+				if _, err := s.conn(ctx).ExecContext(ctx, testQuery); err != nil {
+					return err
+				}
+				return tt.fnErr
+			})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCalls, calls)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestStorage_WithinTx_Nested(t *testing.T) {
+	tests := []struct {
+		name        string
+		innerErr    error
+		expectedErr error
+	}{
+		{
+			name: "nested call joins the outer transaction",
+		},
+		{
+			name:        "error in nested call rolls back the outer transaction",
+			innerErr:    errors.New("inner error"),
+			expectedErr: errors.New("inner error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+
+			// Одна транзакция на оба уровня: вложенный WithinTx не открывает новую.
+			mock.ExpectBegin()
+			mock.ExpectExec(testQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(testQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+			if tt.expectedErr == nil {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			s := &Storage{db: db}
+			err = s.WithinTx(context.Background(), func(ctx context.Context) error {
+				if _, err := s.conn(ctx).ExecContext(ctx, testQuery); err != nil {
+					return err
+				}
+
+				return s.WithinTx(ctx, func(ctx context.Context) error {
+					if _, err := s.conn(ctx).ExecContext(ctx, testQuery); err != nil {
+						return err
+					}
+					return tt.innerErr
+				})
+			})
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestStorage_WithinTx_Panic(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(testQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	s := &Storage{db: db}
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = s.WithinTx(context.Background(), func(ctx context.Context) error {
+			if _, err := s.conn(ctx).ExecContext(ctx, testQuery); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
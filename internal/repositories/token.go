@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/derticom/merch-store/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func (s *Storage) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err := s.conn(ctx).ExecContext(ctx, query, token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt)
+	return err
+}
+
+// GetRefreshTokenByHash возвращает токен по хешу и блокирует его строку до конца транзакции,
+// чтобы один и тот же токен нельзя было параллельно обменять дважды.
+func (s *Storage) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	query := `SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at
+		FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`
+	row := s.conn(ctx).QueryRowContext(ctx, query, tokenHash)
+
+	var token models.RefreshToken
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (s *Storage) MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = $1`
+	_, err := s.conn(ctx).ExecContext(ctx, query, id)
+	return err
+}
+
+func (s *Storage) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE family_id = $1 AND revoked_at IS NULL`
+	_, err := s.conn(ctx).ExecContext(ctx, query, familyID)
+	return err
+}
+
+// RevokeAccessToken добавляет jti в список отозванных. Записи с истекшим сроком больше не нужны,
+// так как такие токены и без того не проходят проверку, поэтому они удаляются здесь же.
+func (s *Storage) RevokeAccessToken(ctx context.Context, jti uuid.UUID, expiresAt time.Time) error {
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		query := `DELETE FROM revoked_access_tokens WHERE expires_at < CURRENT_TIMESTAMP`
+		if _, err := s.conn(ctx).ExecContext(ctx, query); err != nil {
+			return err
+		}
+
+		query = `INSERT INTO revoked_access_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING`
+		_, err := s.conn(ctx).ExecContext(ctx, query, jti, expiresAt)
+		return err
+	})
+}
+
+func (s *Storage) IsAccessTokenRevoked(ctx context.Context, jti uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM revoked_access_tokens WHERE jti = $1)`
+
+	var revoked bool
+	if err := s.conn(ctx).QueryRowContext(ctx, query, jti).Scan(&revoked); err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
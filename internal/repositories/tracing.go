@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/derticom/merch-store/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedConn создает спан на каждый запрос к базе данных.
+type tracedConn struct {
+	conn dbtx
+}
+
+func queryAttrs(query string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBQueryText(query),
+	}
+}
+
+func (c tracedConn) ExecContext(ctx context.Context, query string, args ...any) (_ sql.Result, err error) {
+	ctx, span := tracing.Start(ctx, "db.exec", queryAttrs(query)...)
+	defer tracing.End(span, &err)
+
+	return c.conn.ExecContext(ctx, query, args...)
+}
+
+// QueryContext начинает спан, который завершается при закрытии результата, чтобы в него вошло
+// чтение строк и ошибки, возникшие при их обходе.
+func (c tracedConn) QueryContext(ctx context.Context, query string, args ...any) (*tracedRows, error) {
+	ctx, span := tracing.Start(ctx, "db.query", queryAttrs(query)...)
+
+	rows, err := c.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		tracing.End(span, &err)
+		return nil, err
+	}
+
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+// QueryRowContext начинает спан, который завершается в Scan.
+func (c tracedConn) QueryRowContext(ctx context.Context, query string, args ...any) *tracedRow {
+	ctx, span := tracing.Start(ctx, "db.query", queryAttrs(query)...)
+
+	return &tracedRow{Row: c.conn.QueryRowContext(ctx, query, args...), span: span}
+}
+
+// tracedRows - результат запроса, завершающий спан при закрытии.
+type tracedRows struct {
+	*sql.Rows
+	span trace.Span
+	// scanErr - первая ошибка Scan; sql.Rows не возвращает ее из Err.
+	scanErr error
+	closed  bool
+}
+
+func (r *tracedRows) Scan(dest ...any) error {
+	err := r.Rows.Scan(dest...)
+	if err != nil && r.scanErr == nil {
+		r.scanErr = err
+	}
+
+	return err
+}
+
+func (r *tracedRows) Close() error {
+	rowsErr := r.Rows.Err()
+	err := r.Rows.Close()
+	if !r.closed {
+		r.closed = true
+		spanErr := errors.Join(rowsErr, r.scanErr, err)
+		tracing.End(r.span, &spanErr)
+	}
+
+	return err
+}
+
+// tracedRow - результат запроса одной строки, завершающий спан при чтении.
+type tracedRow struct {
+	*sql.Row
+	span trace.Span
+}
+
+// Scan завершает спан. Отсутствие строки ошибкой запроса не считается.
+func (r *tracedRow) Scan(dest ...any) error {
+	err := r.Row.Scan(dest...)
+	spanErr := err
+	if errors.Is(err, sql.ErrNoRows) {
+		spanErr = nil
+	}
+	tracing.End(r.span, &spanErr)
+
+	return err
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans подменяет глобальный TracerProvider на время теста.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+func TestTracedConn_QueryContext(t *testing.T) {
+	recorder := recordSpans(t)
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rowErr := errors.New("connection reset")
+	mock.ExpectQuery("SELECT name FROM items").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("cup").AddRow("pen").RowError(1, rowErr))
+
+	rows, err := tracedConn{conn: db}.QueryContext(context.Background(), "SELECT name FROM items")
+	require.NoError(t, err)
+
+	var names []string
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		names = append(names, name)
+	}
+	assert.Equal(t, []string{"cup"}, names)
+	assert.ErrorIs(t, rows.Err(), rowErr)
+	assert.Empty(t, recorder.Ended(), "span must stay open until rows are closed")
+
+	require.NoError(t, rows.Close())
+	require.NoError(t, rows.Close())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Status().Description, rowErr.Error())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTracedConn_QueryContext_Error(t *testing.T) {
+	recorder := recordSpans(t)
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	queryErr := errors.New("syntax error")
+	mock.ExpectQuery("SELECT name FROM items").WillReturnError(queryErr)
+
+	rows, err := tracedConn{conn: db}.QueryContext(context.Background(), "SELECT name FROM items")
+	assert.ErrorIs(t, err, queryErr)
+	assert.Nil(t, rows)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTracedConn_QueryRowContext(t *testing.T) {
+	scanErr := errors.New("connection reset")
+
+	tests := []struct {
+		name           string
+		setup          func(mock sqlmock.Sqlmock)
+		expectedErr    error
+		expectedStatus codes.Code
+	}{
+		{
+			name: "row is found",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT coins FROM users").WillReturnRows(sqlmock.NewRows([]string{"coins"}).AddRow(100))
+			},
+			expectedStatus: codes.Unset,
+		},
+		{
+			name: "missing row is not a span error",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT coins FROM users").WillReturnRows(sqlmock.NewRows([]string{"coins"}))
+			},
+			expectedErr:    sql.ErrNoRows,
+			expectedStatus: codes.Unset,
+		},
+		{
+			name: "query error is recorded",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT coins FROM users").WillReturnError(scanErr)
+			},
+			expectedErr:    scanErr,
+			expectedStatus: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := recordSpans(t)
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.setup(mock)
+
+			row := tracedConn{conn: db}.QueryRowContext(context.Background(), "SELECT coins FROM users")
+			assert.Empty(t, recorder.Ended(), "span must stay open until the row is scanned")
+
+			var coins int
+			err = row.Scan(&coins)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, tt.expectedStatus, spans[0].Status().Code)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -2,22 +2,24 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 
 	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
 
 	"github.com/google/uuid"
 )
 
 func (s *Storage) CreateTransaction(ctx context.Context, transaction *models.Transaction) error {
 	query := `INSERT INTO transactions (id, from_user, to_user, amount) VALUES ($1, $2, $3, $4)`
-	_, err := s.db.ExecContext(ctx, query, transaction.ID, transaction.FromUser, transaction.ToUser, transaction.Amount)
+	_, err := s.conn(ctx).ExecContext(ctx, query, transaction.ID, transaction.FromUser, transaction.ToUser, transaction.Amount)
 	return err
 }
 
 func (s *Storage) GetTransactionsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Transaction, error) {
 	query := `SELECT id, from_user, to_user, amount, created_at FROM transactions WHERE from_user = $1 OR to_user = $1`
-	rows, err := s.db.QueryContext(ctx, query, userID)
+	rows, err := s.conn(ctx).QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -45,51 +47,93 @@ func (s *Storage) GetTransactionsByUserID(ctx context.Context, userID uuid.UUID)
 	return transactions, nil
 }
 
-func (s *Storage) SendCoins(ctx context.Context, fromUserID, toUserID uuid.UUID, amount int) error {
-	tx, err := s.db.BeginTx(ctx, nil)
+// GetReceivedTransfers возвращает входящие переводы пользователя с именами отправителей.
+func (s *Storage) GetReceivedTransfers(ctx context.Context, userID uuid.UUID) ([]models.ReceivedTransfer, error) {
+	query := `SELECT u.username, t.amount
+		FROM transactions t
+		JOIN users u ON u.id = t.from_user
+		WHERE t.to_user = $1
+		ORDER BY t.created_at`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer tx.Rollback()
+	defer rows.Close()
 
-	var fromUserCoins int
-	query := `SELECT coins FROM users WHERE id = $1`
-	err = tx.QueryRowContext(ctx, query, fromUserID).Scan(&fromUserCoins)
-	if err != nil {
-		return err
+	transfers := make([]models.ReceivedTransfer, 0)
+	for rows.Next() {
+		var transfer models.ReceivedTransfer
+		if err = rows.Scan(&transfer.FromUser, &transfer.Amount); err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
 	}
 
-	if fromUserCoins < amount {
-		return errors.New("insufficient coins")
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
-	query = `UPDATE users SET coins = coins - $1 WHERE id = $2`
-	_, err = tx.ExecContext(ctx, query, amount, fromUserID)
-	if err != nil {
-		return err
-	}
+	return transfers, nil
+}
 
-	query = `UPDATE users SET coins = coins + $1 WHERE id = $2`
-	_, err = tx.ExecContext(ctx, query, amount, toUserID)
+// GetSentTransfers возвращает исходящие переводы пользователя с именами получателей.
+func (s *Storage) GetSentTransfers(ctx context.Context, userID uuid.UUID) ([]models.SentTransfer, error) {
+	query := `SELECT u.username, t.amount
+		FROM transactions t
+		JOIN users u ON u.id = t.to_user
+		WHERE t.from_user = $1
+		ORDER BY t.created_at`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer rows.Close()
 
-	transaction := &models.Transaction{
-		ID:       uuid.New(),
-		FromUser: fromUserID,
-		ToUser:   toUserID,
-		Amount:   amount,
-	}
-	query = `INSERT INTO transactions (id, from_user, to_user, amount) VALUES ($1, $2, $3, $4)`
-	_, err = tx.ExecContext(ctx, query, transaction.ID, transaction.FromUser, transaction.ToUser, transaction.Amount)
-	if err != nil {
-		return err
+	transfers := make([]models.SentTransfer, 0)
+	for rows.Next() {
+		var transfer models.SentTransfer
+		if err = rows.Scan(&transfer.ToUser, &transfer.Amount); err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return err
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return transfers, nil
+}
+
+func (s *Storage) SendCoins(ctx context.Context, fromUserID, toUserID uuid.UUID, amount int) error {
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		var fromUserCoins int
+		query := `SELECT coins FROM users WHERE id = $1 FOR UPDATE`
+		err := s.conn(ctx).QueryRowContext(ctx, query, fromUserID).Scan(&fromUserCoins)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return services.ErrUserNotFound
+			}
+			return err
+		}
+
+		if fromUserCoins < amount {
+			return services.ErrInsufficientCoins
+		}
+
+		transaction := &models.Transaction{
+			ID:       uuid.New(),
+			FromUser: fromUserID,
+			ToUser:   toUserID,
+			Amount:   amount,
+		}
+		if err := s.CreateTransaction(ctx, transaction); err != nil {
+			return err
+		}
+
+		return s.post(ctx, models.PostingTransfer, &transaction.ID, "",
+			models.UserEntry(fromUserID, -amount),
+			models.UserEntry(toUserID, amount),
+		)
+	})
 }
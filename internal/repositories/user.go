@@ -6,22 +6,41 @@ import (
 	"errors"
 
 	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services"
 
 	"github.com/google/uuid"
 )
 
+// CreateUser создает пользователя. Начальный баланс user.Coins зачисляется проводкой журнала.
 func (s *Storage) CreateUser(ctx context.Context, user *models.User) error {
-	query := `INSERT INTO users (id, username, password, coins) VALUES ($1, $2, $3, $4)`
-	_, err := s.db.ExecContext(ctx, query, user.ID, user.Username, user.Password, user.Coins)
-	return err
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		query := `INSERT INTO users (id, username, password, coins, role) VALUES ($1, $2, $3, 0, $4)`
+		_, err := s.conn(ctx).ExecContext(ctx, query, user.ID, user.Username, user.Password, user.Role)
+		if isUniqueViolation(err) {
+			// Имя могли занять параллельно между проверкой в сервисе и вставкой.
+			return services.ErrUsernameTaken
+		}
+		if err != nil {
+			return err
+		}
+
+		if user.Coins == 0 {
+			return nil
+		}
+
+		return s.post(ctx, models.PostingGrant, &user.ID, "initial balance",
+			models.SystemEntry(models.AccountIssuance, -user.Coins),
+			models.UserEntry(user.ID, user.Coins),
+		)
+	})
 }
 
 func (s *Storage) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
-	query := `SELECT id, username, password, coins FROM users WHERE id = $1`
-	row := s.db.QueryRowContext(ctx, query, id)
+	query := `SELECT id, username, password, coins, role FROM users WHERE id = $1`
+	row := s.conn(ctx).QueryRowContext(ctx, query, id)
 
 	var user models.User
-	err := row.Scan(&user.ID, &user.Username, &user.Password, &user.Coins)
+	err := row.Scan(&user.ID, &user.Username, &user.Password, &user.Coins, &user.Role)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -31,12 +50,27 @@ func (s *Storage) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User,
 	return &user, nil
 }
 
+// LockUser блокирует строку пользователя до конца текущей транзакции. Вне транзакции блокировка
+// снимается сразу, поэтому метод вызывается внутри WithinTx.
+func (s *Storage) LockUser(ctx context.Context, id uuid.UUID) error {
+	var locked uuid.UUID
+	query := `SELECT id FROM users WHERE id = $1 FOR UPDATE`
+	if err := s.conn(ctx).QueryRowContext(ctx, query, id).Scan(&locked); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return services.ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
 func (s *Storage) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
-	query := `SELECT id, username, password, coins FROM users WHERE username = $1`
-	row := s.db.QueryRowContext(ctx, query, username)
+	query := `SELECT id, username, password, coins, role FROM users WHERE username = $1`
+	row := s.conn(ctx).QueryRowContext(ctx, query, username)
 
 	var user models.User
-	err := row.Scan(&user.ID, &user.Username, &user.Password, &user.Coins)
+	err := row.Scan(&user.ID, &user.Username, &user.Password, &user.Coins, &user.Role)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -46,8 +80,30 @@ func (s *Storage) GetUserByUsername(ctx context.Context, username string) (*mode
 	return &user, nil
 }
 
+// UpdateUserCoins устанавливает баланс пользователя, записывая в журнал корректировку на разницу
+// с текущим балансом.
 func (s *Storage) UpdateUserCoins(ctx context.Context, id uuid.UUID, coins int) error {
-	query := `UPDATE users SET coins = $1 WHERE id = $2`
-	_, err := s.db.ExecContext(ctx, query, coins, id)
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		var current int
+		query := `SELECT coins FROM users WHERE id = $1 FOR UPDATE`
+		if err := s.conn(ctx).QueryRowContext(ctx, query, id).Scan(&current); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return services.ErrUserNotFound
+			}
+			return err
+		}
+
+		delta := coins - current
+		if delta == 0 {
+			return nil
+		}
+
+		return s.AdjustUserBalance(ctx, id, delta, "")
+	})
+}
+
+func (s *Storage) UpdateUserRole(ctx context.Context, id uuid.UUID, role string) error {
+	query := `UPDATE users SET role = $1 WHERE id = $2`
+	_, err := s.conn(ctx).ExecContext(ctx, query, role, id)
 	return err
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"time"
 )
@@ -17,11 +18,13 @@ const (
 )
 
 type Server struct {
-	httpServer *http.Server
-	log        *slog.Logger
+	httpServer      *http.Server
+	shutdownTimeout time.Duration
+	onShutdown      []func()
+	log             *slog.Logger
 }
 
-func New(port string, handler http.Handler, log *slog.Logger) *Server {
+func New(port string, handler http.Handler, shutdownTimeout time.Duration, log *slog.Logger) *Server {
 	return &Server{
 		httpServer: &http.Server{
 			Addr:              ":" + port,
@@ -31,16 +34,59 @@ func New(port string, handler http.Handler, log *slog.Logger) *Server {
 			IdleTimeout:       idleTimeout,
 			ReadHeaderTimeout: readHeaderTimeout,
 		},
-		log: log,
+		shutdownTimeout: shutdownTimeout,
+		log:             log,
 	}
 }
 
-func (s *Server) Start() error {
-	s.log.Info("Server is running", "port", s.httpServer.Addr)
-	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		return fmt.Errorf("server error: %w", err)
+// RegisterOnShutdown добавляет функцию, которая синхронно вызывается в начале остановки сервера,
+// до того как он перестанет принимать соединения.
+func (s *Server) RegisterOnShutdown(f func()) {
+	s.onShutdown = append(s.onShutdown, f)
+}
+
+// Run запускает сервер и блокируется до отмены ctx или ошибки сервера. После отмены ctx сервер
+// перестает принимать новые соединения и ждет завершения текущих запросов не дольше shutdownTimeout.
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to net.Listen: %w", err)
+	}
+
+	return s.serve(ctx, listener)
+}
+
+func (s *Server) serve(ctx context.Context, listener net.Listener) error {
+	errCh := make(chan error, 1)
+	go func() {
+		s.log.Info("Server is running", "addr", listener.Addr().String())
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- fmt.Errorf("server error: %w", err)
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
 	}
-	return nil
+
+	s.log.Info("Server is shutting down", "timeout", s.shutdownTimeout)
+
+	for _, f := range s.onShutdown {
+		f()
+	}
+
+	// Контекст родителя уже отменен, поэтому на ожидание запросов выделяем отдельный таймаут.
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.shutdownTimeout)
+	defer cancel()
+
+	if err := s.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shutdown server: %w", err)
+	}
+
+	return <-errCh
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
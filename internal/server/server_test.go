@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	const requestDuration = 300 * time.Millisecond
+
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		time.Sleep(requestDuration)
+		w.Write([]byte("done"))
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := "http://" + listener.Addr().String()
+
+	srv := New("0", handler, 5*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	var hookCalled atomic.Bool
+	srv.RegisterOnShutdown(func() { hookCalled.Store(true) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- srv.serve(ctx, listener)
+	}()
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	respCh := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(addr) //nolint:noctx // test request.
+		if err != nil {
+			respCh <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		respCh <- result{status: resp.StatusCode, body: string(body), err: err}
+	}()
+
+	// Останавливаем сервер, пока запрос еще обрабатывается.
+	<-started
+	cancel()
+
+	res := <-respCh
+	require.NoError(t, res.err)
+	assert.Equal(t, http.StatusOK, res.status)
+	assert.Equal(t, "done", res.body)
+
+	select {
+	case err := <-runErr:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+	assert.True(t, hookCalled.Load(), "shutdown hook was not called")
+
+	// После остановки новые соединения не принимаются.
+	_, err = http.Get(addr) //nolint:noctx // test request.
+	assert.Error(t, err)
+}
+
+func TestServer_ShutdownTimeout(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := "http://" + listener.Addr().String()
+
+	srv := New("0", handler, 100*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- srv.serve(ctx, listener)
+	}()
+
+	go func() {
+		resp, err := http.Get(addr) //nolint:noctx // test request.
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	<-started
+	cancel()
+
+	// Запрос не укладывается в таймаут, поэтому Run возвращает ошибку, а не висит бесконечно.
+	select {
+	case err := <-runErr:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not respect shutdown timeout")
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tracing"
+
+	"github.com/google/uuid"
+)
+
+const refreshTokenBytes = 32
+
+// IssueRefreshToken выдает refresh-токен при входе. Каждый вход открывает новое семейство токенов.
+func (s *Service) IssueRefreshToken(ctx context.Context, userID uuid.UUID) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "Service.IssueRefreshToken")
+	defer tracing.End(span, &err)
+
+	return s.createRefreshToken(ctx, userID, uuid.New())
+}
+
+// RefreshSession обменивает refresh-токен на новый и возвращает владельца с актуальной ролью. Каждый токен одноразовый:
+// повторное предъявление уже обмененного токена означает, что он утек, поэтому отзывается все семейство,
+// и после этого не работает ни токен злоумышленника, ни токен пользователя.
+func (s *Service) RefreshSession(ctx context.Context, refreshToken string) (_ *models.User, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "Service.RefreshSession")
+	defer tracing.End(span, &err)
+
+	var (
+		user     *models.User
+		newToken string
+		reused   bool
+	)
+	err = s.repo.WithinTx(ctx, func(ctx context.Context) error {
+		reused = false
+
+		token, err := s.repo.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+		if err != nil {
+			return err
+		}
+		if token == nil || token.RevokedAt != nil {
+			return ErrInvalidRefreshToken
+		}
+		if token.UsedAt != nil {
+			// Отзыв должен зафиксироваться, поэтому ошибка возвращается уже после транзакции.
+			reused = true
+			return s.repo.RevokeRefreshTokenFamily(ctx, token.FamilyID)
+		}
+		if !token.ExpiresAt.After(time.Now()) {
+			return ErrRefreshTokenExpired
+		}
+
+		if err := s.repo.MarkRefreshTokenUsed(ctx, token.ID); err != nil {
+			return err
+		}
+
+		user, err = s.repo.GetUserByID(ctx, token.UserID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrInvalidRefreshToken
+		}
+
+		newToken, err = s.createRefreshToken(ctx, token.UserID, token.FamilyID)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if reused {
+		return nil, "", ErrRefreshTokenReused
+	}
+
+	return user, newToken, nil
+}
+
+// Logout отзывает текущий access-токен до истечения его срока и, если передан refresh-токен
+// этого пользователя, все семейство refresh-токенов. Чужой или неизвестный refresh-токен игнорируется.
+func (s *Service) Logout(
+	ctx context.Context,
+	userID uuid.UUID,
+	refreshToken string,
+	jti uuid.UUID,
+	accessExpiresAt time.Time,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "Service.Logout")
+	defer tracing.End(span, &err)
+
+	return s.repo.WithinTx(ctx, func(ctx context.Context) error {
+		if refreshToken != "" {
+			token, err := s.repo.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+			if err != nil {
+				return err
+			}
+			if token != nil && token.UserID == userID {
+				if err := s.repo.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+					return err
+				}
+			}
+		}
+
+		return s.repo.RevokeAccessToken(ctx, jti, accessExpiresAt)
+	})
+}
+
+func (s *Service) IsAccessTokenRevoked(ctx context.Context, jti uuid.UUID) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "Service.IsAccessTokenRevoked")
+	defer tracing.End(span, &err)
+
+	return s.repo.IsAccessTokenRevoked(ctx, jti)
+}
+
+func (s *Service) createRefreshToken(ctx context.Context, userID, familyID uuid.UUID) (string, error) {
+	raw := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(raw)
+
+	token := &models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.refreshTokenTTL),
+	}
+	if err := s.repo.CreateRefreshToken(ctx, token); err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+// hashToken возвращает хеш refresh-токена для хранения в БД. Токен случайный и длинный,
+// поэтому медленный хеш вроде bcrypt не нужен, а SHA-256 позволяет искать токен по индексу.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_RefreshSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	const refreshToken = "refresh-token"
+	usedAt := time.Now().Add(-time.Minute)
+	active := &models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	owner := &models.User{ID: active.UserID, Role: models.RoleAdmin}
+
+	withinTx := func() {
+		mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	tests := []struct {
+		name        string
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name: "token is rotated",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hashToken(refreshToken)).Return(active, nil)
+				mockRepo.EXPECT().MarkRefreshTokenUsed(gomock.Any(), active.ID).Return(nil)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), active.UserID).Return(owner, nil)
+				mockRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, token *models.RefreshToken) error {
+						assert.Equal(t, active.UserID, token.UserID)
+						assert.Equal(t, active.FamilyID, token.FamilyID)
+						assert.NotEqual(t, hashToken(refreshToken), token.TokenHash)
+						return nil
+					})
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "unknown token",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hashToken(refreshToken)).Return(nil, nil)
+			},
+			expectedErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "reused token revokes family",
+			setup: func() {
+				withinTx()
+				used := *active
+				used.UsedAt = &usedAt
+				mockRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hashToken(refreshToken)).Return(&used, nil)
+				mockRepo.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), active.FamilyID).Return(nil)
+			},
+			expectedErr: ErrRefreshTokenReused,
+		},
+		{
+			name: "revoked token",
+			setup: func() {
+				withinTx()
+				revoked := *active
+				revoked.RevokedAt = &usedAt
+				mockRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hashToken(refreshToken)).Return(&revoked, nil)
+			},
+			expectedErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "expired token",
+			setup: func() {
+				withinTx()
+				expired := *active
+				expired.ExpiresAt = time.Now().Add(-time.Second)
+				mockRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hashToken(refreshToken)).Return(&expired, nil)
+			},
+			expectedErr: ErrRefreshTokenExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			user, newToken, err := service.RefreshSession(context.Background(), refreshToken)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, newToken)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, owner, user)
+			assert.NotEmpty(t, newToken)
+			assert.NotEqual(t, refreshToken, newToken)
+		})
+	}
+}
+
+func TestService_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	const refreshToken = "refresh-token"
+	userID := uuid.New()
+	jti := uuid.New()
+	expiresAt := time.Now().Add(time.Minute)
+	familyID := uuid.New()
+
+	withinTx := func() {
+		mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	tests := []struct {
+		name         string
+		setup        func()
+		refreshToken string
+		expectedErr  error
+	}{
+		{
+			name: "revokes access token and refresh family",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hashToken(refreshToken)).
+					Return(&models.RefreshToken{UserID: userID, FamilyID: familyID}, nil)
+				mockRepo.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), familyID).Return(nil)
+				mockRepo.EXPECT().RevokeAccessToken(gomock.Any(), jti, expiresAt).Return(nil)
+			},
+			refreshToken: refreshToken,
+		},
+		{
+			name: "foreign refresh token is ignored",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hashToken(refreshToken)).
+					Return(&models.RefreshToken{UserID: uuid.New(), FamilyID: familyID}, nil)
+				mockRepo.EXPECT().RevokeAccessToken(gomock.Any(), jti, expiresAt).Return(nil)
+			},
+			refreshToken: refreshToken,
+		},
+		{
+			name: "without refresh token",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().RevokeAccessToken(gomock.Any(), jti, expiresAt).Return(nil)
+			},
+			refreshToken: "",
+		},
+		{
+			name: "repository error",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().RevokeAccessToken(gomock.Any(), jti, expiresAt).Return(errors.New("db error"))
+			},
+			refreshToken: "",
+			expectedErr:  errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			err := service.Logout(context.Background(), userID, tt.refreshToken, jti, expiresAt)
+
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
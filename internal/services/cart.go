@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+
+	"github.com/derticom/merch-store/internal/metrics"
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tracing"
+
+	"github.com/google/uuid"
+)
+
+// AddToCart добавляет товар в корзину. Остаток здесь не резервируется и проверяется при оформлении заказа.
+func (s *Service) AddToCart(
+	ctx context.Context,
+	userID uuid.UUID,
+	itemName string,
+	quantity int,
+) (_ *models.Cart, err error) {
+	ctx, span := tracing.Start(ctx, "Service.AddToCart")
+	defer tracing.End(span, &err)
+
+	if err := validateQuantity(quantity); err != nil {
+		return nil, err
+	}
+
+	item, err := s.repo.GetItemByName(ctx, itemName)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrItemNotFound
+	}
+	if item.RetiredAt != nil {
+		return nil, ErrItemRetired
+	}
+
+	if err := s.repo.AddToCart(ctx, userID, itemName, quantity); err != nil {
+		return nil, err
+	}
+
+	return s.cart(ctx, userID)
+}
+
+func (s *Service) RemoveFromCart(ctx context.Context, userID uuid.UUID, itemName string) (_ *models.Cart, err error) {
+	ctx, span := tracing.Start(ctx, "Service.RemoveFromCart")
+	defer tracing.End(span, &err)
+
+	if err := s.repo.RemoveFromCart(ctx, userID, itemName); err != nil {
+		return nil, err
+	}
+
+	return s.cart(ctx, userID)
+}
+
+func (s *Service) GetCart(ctx context.Context, userID uuid.UUID) (_ *models.Cart, err error) {
+	ctx, span := tracing.Start(ctx, "Service.GetCart")
+	defer tracing.End(span, &err)
+
+	return s.cart(ctx, userID)
+}
+
+// Checkout покупает все товары из корзины по текущим ценам и убирает купленное из корзины. Все строки
+// покупаются в одной транзакции: если хотя бы одну купить нельзя, заказ не создается и корзина не меняется.
+func (s *Service) Checkout(ctx context.Context, userID uuid.UUID) (_ *models.Order, err error) {
+	ctx, span := tracing.Start(ctx, "Service.Checkout")
+	defer tracing.End(span, &err)
+
+	var order *models.Order
+	err = s.repo.WithinTx(ctx, func(ctx context.Context) error {
+		// Корзина читается под блокировкой пользователя: параллельное оформление дождется этой транзакции
+		// и увидит уже очищенную корзину, а не купит ее содержимое второй раз.
+		if err := s.repo.LockUser(ctx, userID); err != nil {
+			return err
+		}
+
+		lines, err := s.repo.GetCart(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(lines) == 0 {
+			return ErrEmptyCart
+		}
+
+		orderLines := make([]models.OrderLine, 0, len(lines))
+		for _, line := range lines {
+			orderLines = append(orderLines, models.OrderLine{Item: line.Item, Quantity: line.Quantity})
+		}
+
+		order, err = s.repo.BuyItems(ctx, userID, orderLines)
+		if err != nil {
+			return err
+		}
+
+		return s.repo.ClearCart(ctx, userID, orderLines)
+	})
+	if err != nil {
+		metrics.FailedPurchasesTotal.WithLabelValues(failureReason(err)).Inc()
+		return nil, err
+	}
+
+	recordPurchases(order)
+
+	return order, nil
+}
+
+func (s *Service) cart(ctx context.Context, userID uuid.UUID) (*models.Cart, error) {
+	lines, err := s.repo.GetCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := &models.Cart{Items: lines}
+	for _, line := range lines {
+		cart.Total += line.Subtotal
+	}
+
+	return cart, nil
+}
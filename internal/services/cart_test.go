@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Checkout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	userID := uuid.New()
+	lines := []models.CartLine{
+		{Item: "cup", Quantity: 2, Price: 20, Subtotal: 40, Available: true},
+		{Item: "pen", Quantity: 1, Price: 10, Subtotal: 10, Available: true},
+	}
+	orderLines := []models.OrderLine{{Item: "cup", Quantity: 2}, {Item: "pen", Quantity: 1}}
+	order := &models.Order{
+		Purchases: []models.Purchase{
+			{Item: "cup", Price: 20},
+			{Item: "cup", Price: 20},
+			{Item: "pen", Price: 10},
+		},
+		Total: 50,
+	}
+
+	withinTx := func() {
+		mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	tests := []struct {
+		name        string
+		setup       func()
+		expected    *models.Order
+		expectedErr error
+	}{
+		{
+			name: "cart is bought and cleared",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().LockUser(gomock.Any(), userID).Return(nil)
+				mockRepo.EXPECT().GetCart(gomock.Any(), userID).Return(lines, nil)
+				mockRepo.EXPECT().BuyItems(gomock.Any(), userID, orderLines).Return(order, nil)
+				mockRepo.EXPECT().ClearCart(gomock.Any(), userID, orderLines).Return(nil)
+			},
+			expected: order,
+		},
+		{
+			name: "empty cart",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().LockUser(gomock.Any(), userID).Return(nil)
+				mockRepo.EXPECT().GetCart(gomock.Any(), userID).Return([]models.CartLine{}, nil)
+			},
+			expectedErr: ErrEmptyCart,
+		},
+		{
+			name: "unavailable line keeps cart",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().LockUser(gomock.Any(), userID).Return(nil)
+				mockRepo.EXPECT().GetCart(gomock.Any(), userID).Return(lines, nil)
+				mockRepo.EXPECT().BuyItems(gomock.Any(), userID, orderLines).Return(nil, ErrOutOfStock)
+			},
+			expectedErr: ErrOutOfStock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			result, err := service.Checkout(context.Background(), userID)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestService_Checkout_Concurrent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	userID := uuid.New()
+	cart := []models.CartLine{{Item: "cup", Quantity: 2, Price: 20, Subtotal: 40, Available: true}}
+
+	// userLock имитирует блокировку строки пользователя: она берется в LockUser и снимается
+	// при завершении транзакции.
+	type lockedKey struct{}
+	var (
+		userLock sync.Mutex
+		mu       sync.Mutex
+		bought   int
+	)
+
+	mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			locked := new(bool)
+			defer func() {
+				if *locked {
+					userLock.Unlock()
+				}
+			}()
+			return fn(context.WithValue(ctx, lockedKey{}, locked))
+		})
+	mockRepo.EXPECT().LockUser(gomock.Any(), userID).Times(2).DoAndReturn(
+		func(ctx context.Context, _ uuid.UUID) error {
+			userLock.Lock()
+			*ctx.Value(lockedKey{}).(*bool) = true
+			return nil
+		})
+	mockRepo.EXPECT().GetCart(gomock.Any(), userID).Times(2).DoAndReturn(
+		func(context.Context, uuid.UUID) ([]models.CartLine, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return slices.Clone(cart), nil
+		})
+	mockRepo.EXPECT().BuyItems(gomock.Any(), userID, gomock.Any()).AnyTimes().DoAndReturn(
+		func(context.Context, uuid.UUID, []models.OrderLine) (*models.Order, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			bought++
+			return &models.Order{Purchases: []models.Purchase{{Item: "cup"}, {Item: "cup"}}, Total: 40}, nil
+		})
+	mockRepo.EXPECT().ClearCart(gomock.Any(), userID, gomock.Any()).AnyTimes().DoAndReturn(
+		func(context.Context, uuid.UUID, []models.OrderLine) error {
+			mu.Lock()
+			defer mu.Unlock()
+			cart = nil
+			return nil
+		})
+
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = service.Checkout(context.Background(), userID)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, bought)
+	assert.ElementsMatch(t, []error{nil, ErrEmptyCart}, errs)
+}
+
+func TestService_AddToCart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	userID := uuid.New()
+	retiredAt := time.Now()
+
+	tests := []struct {
+		name        string
+		setup       func()
+		quantity    int
+		expected    *models.Cart
+		expectedErr error
+	}{
+		{
+			name: "item is added",
+			setup: func() {
+				mockRepo.EXPECT().GetItemByName(gomock.Any(), "cup").Return(&models.Item{Name: "cup", Price: 20}, nil)
+				mockRepo.EXPECT().AddToCart(gomock.Any(), userID, "cup", 3).Return(nil)
+				mockRepo.EXPECT().GetCart(gomock.Any(), userID).Return([]models.CartLine{
+					{Item: "cup", Quantity: 3, Price: 20, Subtotal: 60, Available: true},
+				}, nil)
+			},
+			quantity: 3,
+			expected: &models.Cart{
+				Items: []models.CartLine{{Item: "cup", Quantity: 3, Price: 20, Subtotal: 60, Available: true}},
+				Total: 60,
+			},
+		},
+		{
+			name:        "non-positive quantity",
+			setup:       func() {},
+			quantity:    -1,
+			expectedErr: ErrNonPositiveQuantity,
+		},
+		{
+			name:        "quantity above limit",
+			setup:       func() {},
+			quantity:    MaxOrderQuantity + 1,
+			expectedErr: ErrQuantityTooLarge,
+		},
+		{
+			name: "retired item",
+			setup: func() {
+				mockRepo.EXPECT().GetItemByName(gomock.Any(), "cup").
+					Return(&models.Item{Name: "cup", Price: 20, RetiredAt: &retiredAt}, nil)
+			},
+			quantity:    1,
+			expectedErr: ErrItemRetired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			cart, err := service.AddToCart(context.Background(), userID, "cup", tt.quantity)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, cart)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"strings"
+)
+
+// Категории ошибок бизнес-логики. Обработчики сопоставляют их с HTTP-статусами через errors.Is.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrConflict          = errors.New("conflict")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrValidation        = errors.New("validation failed")
+	ErrUnauthorized      = errors.New("unauthorized")
+)
+
+var (
+	ErrUserNotFound      = newError(ErrNotFound, "user not found")
+	ErrItemNotFound      = newError(ErrNotFound, "item not found")
+	ErrRecipientNotFound = newError(ErrNotFound, "recipient not found")
+	ErrUsernameTaken     = newError(ErrConflict, "username already exists")
+	ErrInsufficientCoins = newError(ErrInsufficientFunds, "insufficient coins")
+	ErrInvalidPassword   = newError(ErrUnauthorized, "invalid password")
+	ErrSelfTransfer      = newError(ErrValidation, "cannot send coins to yourself")
+	ErrNonPositiveAmount = newError(ErrValidation, "amount must be positive")
+	ErrEmptyCredentials  = newError(ErrValidation, "username and password are required")
+	ErrInvalidRole       = newError(ErrValidation, "invalid role")
+	ErrItemExists        = newError(ErrConflict, "item already exists")
+	ErrItemRetired       = newError(ErrConflict, "item is no longer available")
+	ErrEmptyItemName     = newError(ErrValidation, "item name is required")
+	ErrNonPositivePrice  = newError(ErrValidation, "price must be positive")
+	ErrNothingToUpdate   = newError(ErrValidation, "nothing to update")
+	ErrInvalidCursor     = newError(ErrValidation, "invalid cursor")
+	ErrInvalidSort       = newError(ErrValidation, "invalid sort field")
+	ErrInvalidPriceRange = newError(ErrValidation, "invalid price range")
+	ErrInvalidLimit      = newError(ErrValidation, "invalid limit")
+
+	ErrOutOfStock           = newError(ErrConflict, "item is out of stock")
+	ErrPurchaseLimitReached = newError(ErrConflict, "purchase limit for this item reached")
+	ErrNonPositiveQuantity  = newError(ErrValidation, "quantity must be positive")
+	ErrQuantityTooLarge     = newError(ErrValidation, "quantity is too large")
+	ErrOrderTotalTooLarge   = newError(ErrValidation, "order total is too large")
+	ErrNegativeLimit        = newError(ErrValidation, "purchase limit cannot be negative")
+	ErrNegativeStock        = newError(ErrValidation, "stock cannot be negative")
+
+	ErrCartItemNotFound = newError(ErrNotFound, "item is not in the cart")
+	ErrEmptyCart        = newError(ErrValidation, "cart is empty")
+
+	ErrPurchaseNotFound     = newError(ErrNotFound, "purchase not found")
+	ErrRefundWindowExpired  = newError(ErrConflict, "refund window has expired")
+	ErrRefundNotAllowed     = newError(ErrConflict, "refund has already been requested")
+	ErrRefundNotRequested   = newError(ErrConflict, "refund was not requested for this purchase")
+	ErrRefundOrderDelivered = newError(ErrConflict, "purchases from delivered orders cannot be refunded")
+
+	ErrOrderNotFound          = newError(ErrNotFound, "order not found")
+	ErrInvalidOrderTransition = newError(ErrConflict, "order cannot be moved to this status")
+	ErrInvalidOrderStatus     = newError(ErrValidation, "invalid order status")
+
+	ErrInvalidInviteCode = newError(ErrValidation, "invalid invite code")
+
+	ErrEmptyGrantBatch    = newError(ErrValidation, "no grants to apply")
+	ErrGrantBatchTooLarge = newError(ErrValidation, "too many grants in one batch")
+	ErrZeroAmount         = newError(ErrValidation, "amount must not be zero")
+	ErrReasonRequired     = newError(ErrValidation, "reason is required")
+	ErrUsernameRequired   = newError(ErrValidation, "username is required")
+	ErrDuplicateGrant     = newError(ErrValidation, "user appears more than once in the batch")
+
+	ErrIdempotencyKeyReused     = newError(ErrValidation, "idempotency key was used with a different request")
+	ErrIdempotencyKeyInProgress = newError(ErrConflict, "request with this idempotency key is still in progress")
+
+	ErrInvalidRefreshToken = newError(ErrUnauthorized, "invalid refresh token")
+	ErrRefreshTokenExpired = newError(ErrUnauthorized, "refresh token expired")
+	ErrRefreshTokenReused  = newError(ErrUnauthorized, "refresh token reuse detected")
+)
+
+// Error - ошибка бизнес-логики с сообщением для клиента и категорией для сопоставления через errors.Is.
+type Error struct {
+	kind error
+	msg  string
+}
+
+func newError(kind error, msg string) *Error {
+	return &Error{kind: kind, msg: msg}
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.kind
+}
+
+// failureReason возвращает категорию ошибки для меток метрик.
+func failureReason(err error) string {
+	for _, kind := range []error{ErrNotFound, ErrConflict, ErrInsufficientFunds, ErrValidation, ErrUnauthorized} {
+		if errors.Is(err, kind) {
+			return strings.ReplaceAll(kind.Error(), " ", "_")
+		}
+	}
+
+	return "internal"
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tracing"
+
+	"github.com/google/uuid"
+)
+
+// MaxCoinGrantBatch ограничивает число начислений в одном пакете, чтобы транзакция не держала
+// блокировки слишком долго.
+const MaxCoinGrantBatch = 1000
+
+// GrantCoins начисляет или списывает монеты пользователям от имени администратора. Пакет выполняется
+// в одной транзакции: если хотя бы одно начисление не проходит проверку, не применяется ни одно.
+func (s *Service) GrantCoins(
+	ctx context.Context,
+	adminID uuid.UUID,
+	grants []models.CoinGrant,
+) (_ *models.CoinGrantBatch, err error) {
+	ctx, span := tracing.Start(ctx, "Service.GrantCoins")
+	defer tracing.End(span, &err)
+
+	if len(grants) == 0 {
+		return nil, ErrEmptyGrantBatch
+	}
+	if len(grants) > MaxCoinGrantBatch {
+		return nil, ErrGrantBatchTooLarge
+	}
+
+	grants = slices.Clone(grants)
+	seen := make(map[string]struct{}, len(grants))
+	for i := range grants {
+		grants[i].Username = strings.TrimSpace(grants[i].Username)
+		grants[i].Reason = strings.TrimSpace(grants[i].Reason)
+
+		if err := validateGrant(grants[i]); err != nil {
+			return nil, grantError(grants, i, err)
+		}
+		if _, ok := seen[grants[i].Username]; ok {
+			return nil, grantError(grants, i, ErrDuplicateGrant)
+		}
+		seen[grants[i].Username] = struct{}{}
+	}
+
+	// Строки пользователей блокируются в порядке имен, а не в порядке загрузки, чтобы встречные
+	// пакеты не взаимоблокировались. Номера строк в ошибках и порядок результатов - как в пакете.
+	order := make([]int, len(grants))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int { return strings.Compare(grants[a].Username, grants[b].Username) })
+
+	batch := &models.CoinGrantBatch{ID: uuid.New()}
+	err = s.repo.WithinTx(ctx, func(ctx context.Context) error {
+		results := make([]models.CoinGrantResult, len(grants))
+		for _, i := range order {
+			grant := grants[i]
+			user, err := s.repo.GetUserByUsername(ctx, grant.Username)
+			if err != nil {
+				return err
+			}
+			if user == nil {
+				return grantError(grants, i, ErrUserNotFound)
+			}
+
+			balance, err := s.repo.GrantCoins(ctx, batch.ID, adminID, user.ID, grant.Amount, grant.Reason)
+			if errors.Is(err, ErrInsufficientCoins) {
+				return grantError(grants, i, err)
+			}
+			if err != nil {
+				return err
+			}
+
+			results[i] = models.CoinGrantResult{
+				Username: user.Username,
+				Amount:   grant.Amount,
+				Reason:   grant.Reason,
+				Balance:  balance,
+			}
+		}
+		batch.Grants = results
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+func validateGrant(grant models.CoinGrant) error {
+	switch {
+	case grant.Username == "":
+		return ErrUsernameRequired
+	case grant.Amount == 0:
+		return ErrZeroAmount
+	case grant.Reason == "":
+		return ErrReasonRequired
+	default:
+		return nil
+	}
+}
+
+// grantError указывает в ошибке номер и пользователя начисления, чтобы администратор нашел строку
+// в загруженном пакете. Для одиночного начисления ошибка возвращается как есть.
+func grantError(grants []models.CoinGrant, i int, err error) error {
+	if len(grants) == 1 {
+		return err
+	}
+	if grants[i].Username == "" {
+		return fmt.Errorf("grant %d: %w", i+1, err)
+	}
+
+	return fmt.Errorf("grant %d (%s): %w", i+1, grants[i].Username, err)
+}
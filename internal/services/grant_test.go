@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_GrantCoins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	adminID := uuid.New()
+	alice := &models.User{ID: uuid.New(), Username: "alice", Coins: 100}
+	bob := &models.User{ID: uuid.New(), Username: "bob", Coins: 50}
+
+	withinTx := func() {
+		mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	tests := []struct {
+		name        string
+		grants      []models.CoinGrant
+		setup       func()
+		expected    []models.CoinGrantResult
+		expectedErr string
+	}{
+		{
+			name: "grant and deduction in one batch",
+			grants: []models.CoinGrant{
+				{Username: "alice", Amount: 500, Reason: " quarterly award "},
+				{Username: "bob", Amount: -20, Reason: "duplicate payout"},
+			},
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "alice").Return(alice, nil)
+				mockRepo.EXPECT().GrantCoins(gomock.Any(), gomock.Any(), adminID, alice.ID, 500, "quarterly award").
+					Return(600, nil)
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "bob").Return(bob, nil)
+				mockRepo.EXPECT().GrantCoins(gomock.Any(), gomock.Any(), adminID, bob.ID, -20, "duplicate payout").
+					Return(30, nil)
+			},
+			expected: []models.CoinGrantResult{
+				{Username: "alice", Amount: 500, Reason: "quarterly award", Balance: 600},
+				{Username: "bob", Amount: -20, Reason: "duplicate payout", Balance: 30},
+			},
+		},
+		{
+			name: "users are locked in name order",
+			grants: []models.CoinGrant{
+				{Username: "bob", Amount: 5, Reason: "award"},
+				{Username: "alice", Amount: 10, Reason: "award"},
+			},
+			setup: func() {
+				withinTx()
+				gomock.InOrder(
+					mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "alice").Return(alice, nil),
+					mockRepo.EXPECT().GrantCoins(gomock.Any(), gomock.Any(), adminID, alice.ID, 10, "award").
+						Return(110, nil),
+					mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "bob").Return(bob, nil),
+					mockRepo.EXPECT().GrantCoins(gomock.Any(), gomock.Any(), adminID, bob.ID, 5, "award").
+						Return(55, nil),
+				)
+			},
+			expected: []models.CoinGrantResult{
+				{Username: "bob", Amount: 5, Reason: "award", Balance: 55},
+				{Username: "alice", Amount: 10, Reason: "award", Balance: 110},
+			},
+		},
+		{
+			name:        "empty batch",
+			setup:       func() {},
+			expectedErr: "no grants to apply",
+		},
+		{
+			name:        "single grant without reason",
+			grants:      []models.CoinGrant{{Username: "alice", Amount: 10, Reason: "  "}},
+			setup:       func() {},
+			expectedErr: "reason is required",
+		},
+		{
+			name: "zero amount in batch",
+			grants: []models.CoinGrant{
+				{Username: "alice", Amount: 10, Reason: "award"},
+				{Username: "bob", Reason: "award"},
+			},
+			setup:       func() {},
+			expectedErr: "grant 2 (bob): amount must not be zero",
+		},
+		{
+			name: "duplicate user in batch",
+			grants: []models.CoinGrant{
+				{Username: "alice", Amount: 10, Reason: "award"},
+				{Username: "alice", Amount: 20, Reason: "award"},
+			},
+			setup:       func() {},
+			expectedErr: "grant 2 (alice): user appears more than once in the batch",
+		},
+		{
+			name: "unknown user rolls back the batch",
+			grants: []models.CoinGrant{
+				{Username: "alice", Amount: 10, Reason: "award"},
+				{Username: "carol", Amount: 10, Reason: "award"},
+			},
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "alice").Return(alice, nil)
+				mockRepo.EXPECT().GrantCoins(gomock.Any(), gomock.Any(), adminID, alice.ID, 10, "award").Return(110, nil)
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "carol").Return(nil, nil)
+			},
+			expectedErr: "grant 2 (carol): user not found",
+		},
+		{
+			name:   "deduction exceeds balance",
+			grants: []models.CoinGrant{{Username: "bob", Amount: -100, Reason: "correction"}},
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "bob").Return(bob, nil)
+				mockRepo.EXPECT().GrantCoins(gomock.Any(), gomock.Any(), adminID, bob.ID, -100, "correction").
+					Return(0, ErrInsufficientCoins)
+			},
+			expectedErr: "insufficient coins",
+		},
+		{
+			name:   "repository error",
+			grants: []models.CoinGrant{{Username: "alice", Amount: 10, Reason: "award"}},
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "alice").Return(nil, errors.New("repository error"))
+			},
+			expectedErr: "repository error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			batch, err := service.GrantCoins(context.Background(), adminID, tt.grants)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, batch.ID)
+			assert.Equal(t, tt.expected, batch.Grants)
+		})
+	}
+}
+
+func TestService_GrantCoins_ErrorKinds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := New(mock_services.NewMockRepository(ctrl))
+
+	_, err := service.GrantCoins(context.Background(), uuid.New(), []models.CoinGrant{
+		{Username: "alice", Amount: 10, Reason: "award"},
+		{Username: "bob", Amount: 10},
+	})
+
+	assert.ErrorIs(t, err, ErrReasonRequired)
+	assert.ErrorIs(t, err, ErrValidation)
+}
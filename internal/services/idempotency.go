@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tracing"
+
+	"github.com/google/uuid"
+)
+
+// errIdempotentRequestFailed откатывает транзакцию запроса, завершившегося ошибкой.
+var errIdempotentRequestFailed = errors.New("idempotent request failed")
+
+// ExecuteIdempotentRequest выполняет запрос run не больше одного раза для ключа идемпотентности.
+// Ключ занимается, запрос выполняется и его ответ сохраняется в одной транзакции, поэтому изменения
+// запроса и ответ на повторы фиксируются вместе. Повтор, пришедший во время выполнения, ждет окончания
+// транзакции. Если по ключу уже есть ответ на такой же запрос, run не вызывается, а ответ возвращается
+// с replayed = true.
+//
+// Изменения запроса, ответившего ошибкой, откатываются. Ответ 4xx сохраняется уже после отката,
+// ответ 5xx не сохраняется, и запрос можно повторить. Если ответ 4xx сохранить не удалось, он
+// возвращается вместе с ошибкой.
+func (s *Service) ExecuteIdempotentRequest(
+	ctx context.Context,
+	userID uuid.UUID,
+	key, requestHash string,
+	run func(ctx context.Context) *models.IdempotentResponse,
+) (resp *models.IdempotentResponse, replayed bool, err error) {
+	ctx, span := tracing.Start(ctx, "Service.ExecuteIdempotentRequest")
+	defer tracing.End(span, &err)
+
+	expiresAt := time.Now().Add(s.idempotencyKeyTTL)
+	err = s.repo.WithinTx(ctx, func(ctx context.Context) error {
+		resp, replayed = nil, false
+
+		reserved, err := s.repo.ReserveIdempotencyKey(ctx, userID, key, requestHash, expiresAt)
+		if err != nil {
+			return err
+		}
+		if !reserved {
+			replayed = true
+			resp, err = s.storedResponse(ctx, userID, key, requestHash)
+			return err
+		}
+
+		resp = run(ctx)
+		resp.RequestHash = requestHash
+		if resp.StatusCode >= http.StatusBadRequest {
+			return errIdempotentRequestFailed
+		}
+
+		return s.repo.SaveIdempotentResponse(ctx, userID, key, resp, expiresAt)
+	})
+	if errors.Is(err, errIdempotentRequestFailed) {
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return resp, false, nil
+		}
+		return resp, false, s.repo.SaveIdempotentResponse(ctx, userID, key, resp, expiresAt)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return resp, replayed, nil
+}
+
+// storedResponse возвращает сохраненный по ключу ответ, если ключ занят таким же запросом.
+func (s *Service) storedResponse(
+	ctx context.Context,
+	userID uuid.UUID,
+	key, requestHash string,
+) (*models.IdempotentResponse, error) {
+	resp, err := s.repo.GetIdempotentResponse(ctx, userID, key)
+	if err != nil {
+		return nil, err
+	}
+	// Ключ мог быть освобожден после неуспешного запроса между резервированием и чтением,
+	// клиенту достаточно повторить запрос.
+	if resp == nil {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	if resp.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_ExecuteIdempotentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo, WithIdempotencyKeyTTL(time.Hour))
+
+	userID := uuid.New()
+	key := "d0c5e1a4"
+	hash := "hash"
+	stored := &models.IdempotentResponse{
+		RequestHash: hash,
+		StatusCode:  200,
+		ContentType: "application/json",
+		Body:        []byte(`{"total":20}`),
+	}
+	response := func(status int) *models.IdempotentResponse {
+		return &models.IdempotentResponse{RequestHash: hash, StatusCode: status, Body: []byte(`{}`)}
+	}
+
+	// Транзакция отмечается в контексте, чтобы проверить, где сохраняется ответ.
+	type txKey struct{}
+	withinTx := func() {
+		mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(context.WithValue(ctx, txKey{}, true))
+			})
+	}
+	saveResponse := func(status int, inTx bool) {
+		mockRepo.EXPECT().SaveIdempotentResponse(gomock.Any(), userID, key, response(status), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ uuid.UUID, _ string, _ *models.IdempotentResponse, expiresAt time.Time) error {
+				assert.Equal(t, inTx, ctx.Value(txKey{}) != nil)
+				assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Second)
+				return nil
+			})
+	}
+
+	tests := []struct {
+		name             string
+		setup            func()
+		status           int
+		expectedRun      bool
+		expected         *models.IdempotentResponse
+		expectedReplayed bool
+		expectedErr      error
+	}{
+		{
+			name: "response is stored in the request transaction",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().ReserveIdempotencyKey(gomock.Any(), userID, key, hash, gomock.Any()).Return(true, nil)
+				saveResponse(200, true)
+			},
+			status:      200,
+			expectedRun: true,
+			expected:    response(200),
+		},
+		{
+			name: "client error is rolled back and stored",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().ReserveIdempotencyKey(gomock.Any(), userID, key, hash, gomock.Any()).Return(true, nil)
+				saveResponse(422, false)
+			},
+			status:      422,
+			expectedRun: true,
+			expected:    response(422),
+		},
+		{
+			name: "server error is rolled back and not stored",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().ReserveIdempotencyKey(gomock.Any(), userID, key, hash, gomock.Any()).Return(true, nil)
+			},
+			status:      500,
+			expectedRun: true,
+			expected:    response(500),
+		},
+		{
+			name: "failed save rolls back the request",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().ReserveIdempotencyKey(gomock.Any(), userID, key, hash, gomock.Any()).Return(true, nil)
+				mockRepo.EXPECT().SaveIdempotentResponse(gomock.Any(), userID, key, response(200), gomock.Any()).
+					Return(ErrIdempotencyKeyInProgress)
+			},
+			status:      200,
+			expectedRun: true,
+			expectedErr: ErrIdempotencyKeyInProgress,
+		},
+		{
+			name: "stored response is replayed",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().ReserveIdempotencyKey(gomock.Any(), userID, key, hash, gomock.Any()).Return(false, nil)
+				mockRepo.EXPECT().GetIdempotentResponse(gomock.Any(), userID, key).Return(stored, nil)
+			},
+			expected:         stored,
+			expectedReplayed: true,
+		},
+		{
+			name: "key reused with different request",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().ReserveIdempotencyKey(gomock.Any(), userID, key, hash, gomock.Any()).Return(false, nil)
+				mockRepo.EXPECT().GetIdempotentResponse(gomock.Any(), userID, key).
+					Return(&models.IdempotentResponse{RequestHash: "other", StatusCode: 200}, nil)
+			},
+			expectedErr: ErrIdempotencyKeyReused,
+		},
+		{
+			name: "repository error",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().ReserveIdempotencyKey(gomock.Any(), userID, key, hash, gomock.Any()).
+					Return(false, errors.New("connection reset"))
+			},
+			expectedErr: errors.New("connection reset"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			ran := false
+			result, replayed, err := service.ExecuteIdempotentRequest(context.Background(), userID, key, hash,
+				func(ctx context.Context) *models.IdempotentResponse {
+					ran = true
+					assert.NotNil(t, ctx.Value(txKey{}), "request must run in the transaction")
+					return &models.IdempotentResponse{StatusCode: tt.status, Body: []byte(`{}`)}
+				})
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.expectedReplayed, replayed)
+			assert.Equal(t, tt.expectedRun, ran)
+		})
+	}
+}
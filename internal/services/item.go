@@ -2,14 +2,292 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
 
 	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tracing"
+
+	"github.com/google/uuid"
 )
 
-func (s *Service) GetAllItems(ctx context.Context) ([]models.Item, error) {
+func (s *Service) GetAllItems(ctx context.Context) (_ []models.Item, err error) {
+	ctx, span := tracing.Start(ctx, "Service.GetAllItems")
+	defer tracing.End(span, &err)
+
 	return s.repo.GetAllItems(ctx)
 }
 
-func (s *Service) GetItemByName(ctx context.Context, name string) (*models.Item, error) {
+func (s *Service) GetItemByName(ctx context.Context, name string) (_ *models.Item, err error) {
+	ctx, span := tracing.Start(ctx, "Service.GetItemByName")
+	defer tracing.End(span, &err)
+
 	return s.repo.GetItemByName(ctx, name)
 }
+
+func (s *Service) CreateItem(ctx context.Context, name string, price int) (_ *models.Item, err error) {
+	ctx, span := tracing.Start(ctx, "Service.CreateItem")
+	defer tracing.End(span, &err)
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrEmptyItemName
+	}
+	if price <= 0 {
+		return nil, ErrNonPositivePrice
+	}
+
+	item := &models.Item{Name: name, Price: price}
+	if err := s.repo.CreateItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// UpdateItem меняет цену, название и ограничение покупок товара. Незаданные поля не меняются.
+func (s *Service) UpdateItem(ctx context.Context, name string, update models.ItemUpdate) (_ *models.Item, err error) {
+	ctx, span := tracing.Start(ctx, "Service.UpdateItem")
+	defer tracing.End(span, &err)
+
+	if update.Name == nil && update.Price == nil && update.PerUserLimit == nil {
+		return nil, ErrNothingToUpdate
+	}
+	if update.Price != nil && *update.Price <= 0 {
+		return nil, ErrNonPositivePrice
+	}
+	if update.PerUserLimit != nil && *update.PerUserLimit < 0 {
+		return nil, ErrNegativeLimit
+	}
+	if update.Name != nil {
+		trimmed := strings.TrimSpace(*update.Name)
+		if trimmed == "" {
+			return nil, ErrEmptyItemName
+		}
+		update.Name = &trimmed
+	}
+
+	var item *models.Item
+	err = s.repo.WithinTx(ctx, func(ctx context.Context) error {
+		current := name
+
+		if update.Price != nil {
+			if err := s.repo.UpdateItemPrice(ctx, current, *update.Price); err != nil {
+				return err
+			}
+		}
+
+		if update.PerUserLimit != nil {
+			limit := update.PerUserLimit
+			if *limit == 0 {
+				limit = nil
+			}
+			if err := s.repo.SetItemPurchaseLimit(ctx, current, limit); err != nil {
+				return err
+			}
+		}
+
+		if update.Name != nil && *update.Name != current {
+			if err := s.repo.RenameItem(ctx, current, *update.Name); err != nil {
+				return err
+			}
+			current = *update.Name
+		}
+
+		var err error
+		item, err = s.repo.GetItemByName(ctx, current)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return ErrItemNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// RestockItem пополняет остаток товара. Каждое пополнение попадает в журнал движений с автором и комментарием.
+func (s *Service) RestockItem(
+	ctx context.Context,
+	adminID uuid.UUID,
+	name string,
+	quantity int,
+	note string,
+) (_ *models.Item, err error) {
+	ctx, span := tracing.Start(ctx, "Service.RestockItem")
+	defer tracing.End(span, &err)
+
+	if quantity <= 0 {
+		return nil, ErrNonPositiveQuantity
+	}
+
+	if err := s.repo.RestockItem(ctx, name, quantity, adminID, note); err != nil {
+		return nil, err
+	}
+
+	item, err := s.repo.GetItemByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrItemNotFound
+	}
+
+	return item, nil
+}
+
+// SetItemStock задает остаток товара: после инвентаризации, при ошибке пополнения или для нового
+// лимитированного товара. nil снимает ограничение количества. Изменение попадает в журнал движений
+// как корректировка.
+func (s *Service) SetItemStock(
+	ctx context.Context,
+	adminID uuid.UUID,
+	name string,
+	stock *int,
+	note string,
+) (_ *models.Item, err error) {
+	ctx, span := tracing.Start(ctx, "Service.SetItemStock")
+	defer tracing.End(span, &err)
+
+	if stock != nil && *stock < 0 {
+		return nil, ErrNegativeStock
+	}
+
+	if err := s.repo.SetItemStock(ctx, name, stock, adminID, note); err != nil {
+		return nil, err
+	}
+
+	item, err := s.repo.GetItemByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrItemNotFound
+	}
+
+	return item, nil
+}
+
+func (s *Service) GetStockMovements(ctx context.Context, name string) (_ []models.StockMovement, err error) {
+	ctx, span := tracing.Start(ctx, "Service.GetStockMovements")
+	defer tracing.End(span, &err)
+
+	item, err := s.repo.GetItemByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrItemNotFound
+	}
+
+	return s.repo.GetStockMovements(ctx, name)
+}
+
+// RetireItem снимает товар с продажи. Купленные ранее товары остаются в истории и инвентаре.
+func (s *Service) RetireItem(ctx context.Context, name string) (_ *models.Item, err error) {
+	ctx, span := tracing.Start(ctx, "Service.RetireItem")
+	defer tracing.End(span, &err)
+
+	if err := s.repo.RetireItem(ctx, name); err != nil {
+		return nil, err
+	}
+
+	item, err := s.repo.GetItemByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrItemNotFound
+	}
+
+	return item, nil
+}
+
+const (
+	defaultItemsLimit = 20
+	maxItemsLimit     = 100
+)
+
+// ListItems возвращает страницу каталога. cursor - значение nextCursor из предыдущего ответа
+// с теми же параметрами сортировки; пустой cursor означает первую страницу.
+func (s *Service) ListItems(ctx context.Context, filter models.ItemFilter, cursor string) (_ *models.ItemPage, err error) {
+	ctx, span := tracing.Start(ctx, "Service.ListItems")
+	defer tracing.End(span, &err)
+
+	if filter.SortBy == "" {
+		filter.SortBy = models.ItemSortName
+	}
+	if filter.SortBy != models.ItemSortName && filter.SortBy != models.ItemSortPrice {
+		return nil, ErrInvalidSort
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultItemsLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxItemsLimit {
+		return nil, ErrInvalidLimit
+	}
+
+	if (filter.MinPrice != nil && *filter.MinPrice < 0) ||
+		(filter.MaxPrice != nil && *filter.MaxPrice < 0) ||
+		(filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice) {
+		return nil, ErrInvalidPriceRange
+	}
+
+	if cursor != "" {
+		after, err := decodeItemCursor(cursor)
+		if err != nil || after.SortBy != filter.SortBy || after.Desc != filter.Desc {
+			return nil, ErrInvalidCursor
+		}
+		filter.After = after
+	}
+
+	// Запрашиваем на один товар больше, чтобы понять, есть ли следующая страница.
+	limit := filter.Limit
+	filter.Limit++
+
+	items, err := s.repo.ListItems(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ItemPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = encodeItemCursor(models.ItemCursor{
+			SortBy: filter.SortBy,
+			Desc:   filter.Desc,
+			Name:   last.Name,
+			Price:  last.Price,
+		})
+	}
+
+	return page, nil
+}
+
+func encodeItemCursor(cursor models.ItemCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeItemCursor(cursor string) (*models.ItemCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded models.ItemCursor
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	return &decoded, nil
+}
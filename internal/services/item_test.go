@@ -9,6 +9,7 @@ import (
 	"github.com/derticom/merch-store/internal/services/mocks"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -121,3 +122,380 @@ func TestService_GetItemByName(t *testing.T) {
 		})
 	}
 }
+
+func TestService_CreateItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	tests := []struct {
+		name        string
+		setup       func()
+		itemName    string
+		price       int
+		expected    *models.Item
+		expectedErr error
+	}{
+		{
+			name: "item is created",
+			setup: func() {
+				mockRepo.EXPECT().CreateItem(gomock.Any(), &models.Item{Name: "sticker", Price: 5}).Return(nil)
+			},
+			itemName: " sticker ",
+			price:    5,
+			expected: &models.Item{Name: "sticker", Price: 5},
+		},
+		{
+			name:        "empty name",
+			setup:       func() {},
+			itemName:    "  ",
+			price:       5,
+			expectedErr: ErrEmptyItemName,
+		},
+		{
+			name:        "non-positive price",
+			setup:       func() {},
+			itemName:    "sticker",
+			price:       0,
+			expectedErr: ErrNonPositivePrice,
+		},
+		{
+			name: "item exists",
+			setup: func() {
+				mockRepo.EXPECT().CreateItem(gomock.Any(), gomock.Any()).Return(ErrItemExists)
+			},
+			itemName:    "cup",
+			price:       20,
+			expectedErr: ErrItemExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			item, err := service.CreateItem(context.Background(), tt.itemName, tt.price)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, item)
+		})
+	}
+}
+
+func TestService_UpdateItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	withinTx := func() {
+		mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+	newName := "mug"
+	newPrice := 25
+	zero := 0
+	limit := 2
+
+	tests := []struct {
+		name        string
+		setup       func()
+		update      models.ItemUpdate
+		expected    *models.Item
+		expectedErr error
+	}{
+		{
+			name: "price and name are updated",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().UpdateItemPrice(gomock.Any(), "cup", newPrice).Return(nil)
+				mockRepo.EXPECT().RenameItem(gomock.Any(), "cup", newName).Return(nil)
+				mockRepo.EXPECT().GetItemByName(gomock.Any(), newName).Return(&models.Item{Name: newName, Price: newPrice}, nil)
+			},
+			update:   models.ItemUpdate{Name: &newName, Price: &newPrice},
+			expected: &models.Item{Name: newName, Price: newPrice},
+		},
+		{
+			name: "unknown item",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().UpdateItemPrice(gomock.Any(), "cup", newPrice).Return(ErrItemNotFound)
+			},
+			update:      models.ItemUpdate{Price: &newPrice},
+			expectedErr: ErrItemNotFound,
+		},
+		{
+			name:        "nothing to update",
+			setup:       func() {},
+			expectedErr: ErrNothingToUpdate,
+		},
+		{
+			name:        "non-positive price",
+			setup:       func() {},
+			update:      models.ItemUpdate{Price: &zero},
+			expectedErr: ErrNonPositivePrice,
+		},
+		{
+			name: "purchase limit is set",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().SetItemPurchaseLimit(gomock.Any(), "cup", &limit).Return(nil)
+				mockRepo.EXPECT().GetItemByName(gomock.Any(), "cup").Return(&models.Item{Name: "cup", PerUserLimit: &limit}, nil)
+			},
+			update:   models.ItemUpdate{PerUserLimit: &limit},
+			expected: &models.Item{Name: "cup", PerUserLimit: &limit},
+		},
+		{
+			name: "zero removes purchase limit",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().SetItemPurchaseLimit(gomock.Any(), "cup", nil).Return(nil)
+				mockRepo.EXPECT().GetItemByName(gomock.Any(), "cup").Return(&models.Item{Name: "cup"}, nil)
+			},
+			update:   models.ItemUpdate{PerUserLimit: &zero},
+			expected: &models.Item{Name: "cup"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			item, err := service.UpdateItem(context.Background(), "cup", tt.update)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, item)
+		})
+	}
+}
+
+func TestService_ListItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	items := []models.Item{
+		{Name: "pen", Price: 10},
+		{Name: "socks", Price: 10},
+		{Name: "cup", Price: 20},
+	}
+	minPrice, maxPrice := 30, 20
+	priceCursor := encodeItemCursor(models.ItemCursor{SortBy: models.ItemSortPrice, Name: "socks", Price: 10})
+
+	tests := []struct {
+		name           string
+		setup          func()
+		filter         models.ItemFilter
+		cursor         string
+		expectedItems  []models.Item
+		expectedCursor bool
+		expectedErr    error
+	}{
+		{
+			name: "first page has next cursor",
+			setup: func() {
+				mockRepo.EXPECT().ListItems(gomock.Any(), models.ItemFilter{SortBy: models.ItemSortPrice, Limit: 3}).
+					Return(items, nil)
+			},
+			filter:         models.ItemFilter{SortBy: models.ItemSortPrice, Limit: 2},
+			expectedItems:  items[:2],
+			expectedCursor: true,
+		},
+		{
+			name: "last page",
+			setup: func() {
+				mockRepo.EXPECT().ListItems(gomock.Any(), models.ItemFilter{
+					SortBy: models.ItemSortPrice,
+					Limit:  3,
+					After:  &models.ItemCursor{SortBy: models.ItemSortPrice, Name: "socks", Price: 10},
+				}).Return(items[2:], nil)
+			},
+			filter:        models.ItemFilter{SortBy: models.ItemSortPrice, Limit: 2},
+			cursor:        priceCursor,
+			expectedItems: items[2:],
+		},
+		{
+			name: "default sort and limit",
+			setup: func() {
+				mockRepo.EXPECT().ListItems(gomock.Any(), models.ItemFilter{SortBy: models.ItemSortName, Limit: defaultItemsLimit + 1}).
+					Return(items, nil)
+			},
+			expectedItems: items,
+		},
+		{
+			name:        "cursor from another sort",
+			setup:       func() {},
+			filter:      models.ItemFilter{SortBy: models.ItemSortName},
+			cursor:      priceCursor,
+			expectedErr: ErrInvalidCursor,
+		},
+		{
+			name:        "malformed cursor",
+			setup:       func() {},
+			cursor:      "!!!",
+			expectedErr: ErrInvalidCursor,
+		},
+		{
+			name:        "unknown sort",
+			setup:       func() {},
+			filter:      models.ItemFilter{SortBy: "popularity"},
+			expectedErr: ErrInvalidSort,
+		},
+		{
+			name:        "limit too large",
+			setup:       func() {},
+			filter:      models.ItemFilter{Limit: maxItemsLimit + 1},
+			expectedErr: ErrInvalidLimit,
+		},
+		{
+			name:        "min price above max price",
+			setup:       func() {},
+			filter:      models.ItemFilter{MinPrice: &minPrice, MaxPrice: &maxPrice},
+			expectedErr: ErrInvalidPriceRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			page, err := service.ListItems(context.Background(), tt.filter, tt.cursor)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedItems, page.Items)
+			assert.Equal(t, tt.expectedCursor, page.NextCursor != "")
+		})
+	}
+}
+
+func TestService_RestockItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	adminID := uuid.New()
+	stock := 10
+
+	tests := []struct {
+		name        string
+		setup       func()
+		quantity    int
+		expected    *models.Item
+		expectedErr error
+	}{
+		{
+			name: "stock is increased",
+			setup: func() {
+				mockRepo.EXPECT().RestockItem(gomock.Any(), "pink-hoody", 10, adminID, "new drop").Return(nil)
+				mockRepo.EXPECT().GetItemByName(gomock.Any(), "pink-hoody").
+					Return(&models.Item{Name: "pink-hoody", Price: 500, Stock: &stock}, nil)
+			},
+			quantity: 10,
+			expected: &models.Item{Name: "pink-hoody", Price: 500, Stock: &stock},
+		},
+		{
+			name:        "non-positive quantity",
+			setup:       func() {},
+			quantity:    0,
+			expectedErr: ErrNonPositiveQuantity,
+		},
+		{
+			name: "unknown item",
+			setup: func() {
+				mockRepo.EXPECT().RestockItem(gomock.Any(), "pink-hoody", 5, adminID, "new drop").Return(ErrItemNotFound)
+			},
+			quantity:    5,
+			expectedErr: ErrItemNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			item, err := service.RestockItem(context.Background(), adminID, "pink-hoody", tt.quantity, "new drop")
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, item)
+		})
+	}
+}
+
+func TestService_SetItemStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	adminID := uuid.New()
+	stock := 3
+	negative := -1
+
+	tests := []struct {
+		name        string
+		setup       func()
+		stock       *int
+		expected    *models.Item
+		expectedErr error
+	}{
+		{
+			name: "stock is set",
+			setup: func() {
+				mockRepo.EXPECT().SetItemStock(gomock.Any(), "pink-hoody", &stock, adminID, "inventory").Return(nil)
+				mockRepo.EXPECT().GetItemByName(gomock.Any(), "pink-hoody").
+					Return(&models.Item{Name: "pink-hoody", Price: 500, Stock: &stock}, nil)
+			},
+			stock:    &stock,
+			expected: &models.Item{Name: "pink-hoody", Price: 500, Stock: &stock},
+		},
+		{
+			name: "item becomes unlimited",
+			setup: func() {
+				mockRepo.EXPECT().SetItemStock(gomock.Any(), "pink-hoody", nil, adminID, "inventory").Return(nil)
+				mockRepo.EXPECT().GetItemByName(gomock.Any(), "pink-hoody").
+					Return(&models.Item{Name: "pink-hoody", Price: 500}, nil)
+			},
+			expected: &models.Item{Name: "pink-hoody", Price: 500},
+		},
+		{
+			name:        "negative stock",
+			setup:       func() {},
+			stock:       &negative,
+			expectedErr: ErrNegativeStock,
+		},
+		{
+			name: "unknown item",
+			setup: func() {
+				mockRepo.EXPECT().SetItemStock(gomock.Any(), "pink-hoody", &stock, adminID, "inventory").
+					Return(ErrItemNotFound)
+			},
+			stock:       &stock,
+			expectedErr: ErrItemNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			item, err := service.SetItemStock(context.Background(), adminID, "pink-hoody", tt.stock, "inventory")
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, item)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tracing"
+)
+
+// CheckLedger пересчитывает балансы по журналу проводок и сообщает о расхождениях с кешированными балансами.
+func (s *Service) CheckLedger(ctx context.Context) (_ *models.LedgerCheck, err error) {
+	ctx, span := tracing.Start(ctx, "Service.CheckLedger")
+	defer tracing.End(span, &err)
+
+	return s.repo.CheckLedger(ctx)
+}
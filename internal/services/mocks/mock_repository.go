@@ -0,0 +1,864 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/derticom/merch-store/internal/services (interfaces: Repository)
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/derticom/merch-store/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddToCart mocks base method.
+func (m *MockRepository) AddToCart(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToCart", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToCart indicates an expected call of AddToCart.
+func (mr *MockRepositoryMockRecorder) AddToCart(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToCart", reflect.TypeOf((*MockRepository)(nil).AddToCart), arg0, arg1, arg2, arg3)
+}
+
+// AdjustUserBalance mocks base method.
+func (m *MockRepository) AdjustUserBalance(arg0 context.Context, arg1 uuid.UUID, arg2 int, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustUserBalance", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdjustUserBalance indicates an expected call of AdjustUserBalance.
+func (mr *MockRepositoryMockRecorder) AdjustUserBalance(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustUserBalance", reflect.TypeOf((*MockRepository)(nil).AdjustUserBalance), arg0, arg1, arg2, arg3)
+}
+
+// BuyItem mocks base method.
+func (m *MockRepository) BuyItem(arg0 context.Context, arg1 uuid.UUID, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyItem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BuyItem indicates an expected call of BuyItem.
+func (mr *MockRepositoryMockRecorder) BuyItem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItem", reflect.TypeOf((*MockRepository)(nil).BuyItem), arg0, arg1, arg2)
+}
+
+// BuyItems mocks base method.
+func (m *MockRepository) BuyItems(arg0 context.Context, arg1 uuid.UUID, arg2 []models.OrderLine) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyItems", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuyItems indicates an expected call of BuyItems.
+func (mr *MockRepositoryMockRecorder) BuyItems(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItems", reflect.TypeOf((*MockRepository)(nil).BuyItems), arg0, arg1, arg2)
+}
+
+// CheckLedger mocks base method.
+func (m *MockRepository) CheckLedger(arg0 context.Context) (*models.LedgerCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLedger", arg0)
+	ret0, _ := ret[0].(*models.LedgerCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckLedger indicates an expected call of CheckLedger.
+func (mr *MockRepositoryMockRecorder) CheckLedger(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLedger", reflect.TypeOf((*MockRepository)(nil).CheckLedger), arg0)
+}
+
+// ClearCart mocks base method.
+func (m *MockRepository) ClearCart(arg0 context.Context, arg1 uuid.UUID, arg2 []models.OrderLine) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearCart", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearCart indicates an expected call of ClearCart.
+func (mr *MockRepositoryMockRecorder) ClearCart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearCart", reflect.TypeOf((*MockRepository)(nil).ClearCart), arg0, arg1, arg2)
+}
+
+// CreateItem mocks base method.
+func (m *MockRepository) CreateItem(arg0 context.Context, arg1 *models.Item) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItem", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateItem indicates an expected call of CreateItem.
+func (mr *MockRepositoryMockRecorder) CreateItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockRepository)(nil).CreateItem), arg0, arg1)
+}
+
+// CreatePurchase mocks base method.
+func (m *MockRepository) CreatePurchase(arg0 context.Context, arg1 *models.Purchase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePurchase", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePurchase indicates an expected call of CreatePurchase.
+func (mr *MockRepositoryMockRecorder) CreatePurchase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePurchase", reflect.TypeOf((*MockRepository)(nil).CreatePurchase), arg0, arg1)
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockRepository) CreateRefreshToken(arg0 context.Context, arg1 *models.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockRepositoryMockRecorder) CreateRefreshToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockRepository)(nil).CreateRefreshToken), arg0, arg1)
+}
+
+// CreateTransaction mocks base method.
+func (m *MockRepository) CreateTransaction(arg0 context.Context, arg1 *models.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransaction", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTransaction indicates an expected call of CreateTransaction.
+func (mr *MockRepositoryMockRecorder) CreateTransaction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockRepository)(nil).CreateTransaction), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockRepository) CreateUser(arg0 context.Context, arg1 *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockRepositoryMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockRepository)(nil).CreateUser), arg0, arg1)
+}
+
+// GetAllItems mocks base method.
+func (m *MockRepository) GetAllItems(arg0 context.Context) ([]models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllItems", arg0)
+	ret0, _ := ret[0].([]models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllItems indicates an expected call of GetAllItems.
+func (mr *MockRepositoryMockRecorder) GetAllItems(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllItems", reflect.TypeOf((*MockRepository)(nil).GetAllItems), arg0)
+}
+
+// GetBalanceReconciliation mocks base method.
+func (m *MockRepository) GetBalanceReconciliation(arg0 context.Context, arg1 uuid.UUID) (*models.BalanceReconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceReconciliation", arg0, arg1)
+	ret0, _ := ret[0].(*models.BalanceReconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceReconciliation indicates an expected call of GetBalanceReconciliation.
+func (mr *MockRepositoryMockRecorder) GetBalanceReconciliation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceReconciliation", reflect.TypeOf((*MockRepository)(nil).GetBalanceReconciliation), arg0, arg1)
+}
+
+// GetBalanceReconciliations mocks base method.
+func (m *MockRepository) GetBalanceReconciliations(arg0 context.Context) ([]models.BalanceReconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceReconciliations", arg0)
+	ret0, _ := ret[0].([]models.BalanceReconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceReconciliations indicates an expected call of GetBalanceReconciliations.
+func (mr *MockRepositoryMockRecorder) GetBalanceReconciliations(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceReconciliations", reflect.TypeOf((*MockRepository)(nil).GetBalanceReconciliations), arg0)
+}
+
+// GetCart mocks base method.
+func (m *MockRepository) GetCart(arg0 context.Context, arg1 uuid.UUID) ([]models.CartLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCart", arg0, arg1)
+	ret0, _ := ret[0].([]models.CartLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCart indicates an expected call of GetCart.
+func (mr *MockRepositoryMockRecorder) GetCart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCart", reflect.TypeOf((*MockRepository)(nil).GetCart), arg0, arg1)
+}
+
+// GetGrantsByUserID mocks base method.
+func (m *MockRepository) GetGrantsByUserID(arg0 context.Context, arg1 uuid.UUID) ([]models.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGrantsByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]models.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGrantsByUserID indicates an expected call of GetGrantsByUserID.
+func (mr *MockRepositoryMockRecorder) GetGrantsByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGrantsByUserID", reflect.TypeOf((*MockRepository)(nil).GetGrantsByUserID), arg0, arg1)
+}
+
+// GetIdempotentResponse mocks base method.
+func (m *MockRepository) GetIdempotentResponse(arg0 context.Context, arg1 uuid.UUID, arg2 string) (*models.IdempotentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotentResponse", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.IdempotentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotentResponse indicates an expected call of GetIdempotentResponse.
+func (mr *MockRepositoryMockRecorder) GetIdempotentResponse(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotentResponse", reflect.TypeOf((*MockRepository)(nil).GetIdempotentResponse), arg0, arg1, arg2)
+}
+
+// GetInventoryByUserID mocks base method.
+func (m *MockRepository) GetInventoryByUserID(arg0 context.Context, arg1 uuid.UUID) ([]models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInventoryByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInventoryByUserID indicates an expected call of GetInventoryByUserID.
+func (mr *MockRepositoryMockRecorder) GetInventoryByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInventoryByUserID", reflect.TypeOf((*MockRepository)(nil).GetInventoryByUserID), arg0, arg1)
+}
+
+// GetItemByName mocks base method.
+func (m *MockRepository) GetItemByName(arg0 context.Context, arg1 string) (*models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemByName", arg0, arg1)
+	ret0, _ := ret[0].(*models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemByName indicates an expected call of GetItemByName.
+func (mr *MockRepositoryMockRecorder) GetItemByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemByName", reflect.TypeOf((*MockRepository)(nil).GetItemByName), arg0, arg1)
+}
+
+// GetOrderByID mocks base method.
+func (m *MockRepository) GetOrderByID(arg0 context.Context, arg1 uuid.UUID) (*models.FulfillmentOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.FulfillmentOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderByID indicates an expected call of GetOrderByID.
+func (mr *MockRepositoryMockRecorder) GetOrderByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockRepository)(nil).GetOrderByID), arg0, arg1)
+}
+
+// GetPurchaseByID mocks base method.
+func (m *MockRepository) GetPurchaseByID(arg0 context.Context, arg1 uuid.UUID) (*models.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchaseByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchaseByID indicates an expected call of GetPurchaseByID.
+func (mr *MockRepositoryMockRecorder) GetPurchaseByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchaseByID", reflect.TypeOf((*MockRepository)(nil).GetPurchaseByID), arg0, arg1)
+}
+
+// GetPurchasesByOrderID mocks base method.
+func (m *MockRepository) GetPurchasesByOrderID(arg0 context.Context, arg1 uuid.UUID) ([]models.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchasesByOrderID", arg0, arg1)
+	ret0, _ := ret[0].([]models.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchasesByOrderID indicates an expected call of GetPurchasesByOrderID.
+func (mr *MockRepositoryMockRecorder) GetPurchasesByOrderID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchasesByOrderID", reflect.TypeOf((*MockRepository)(nil).GetPurchasesByOrderID), arg0, arg1)
+}
+
+// GetPurchasesByUserID mocks base method.
+func (m *MockRepository) GetPurchasesByUserID(arg0 context.Context, arg1 uuid.UUID) ([]models.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchasesByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]models.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchasesByUserID indicates an expected call of GetPurchasesByUserID.
+func (mr *MockRepositoryMockRecorder) GetPurchasesByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchasesByUserID", reflect.TypeOf((*MockRepository)(nil).GetPurchasesByUserID), arg0, arg1)
+}
+
+// GetReceivedTransfers mocks base method.
+func (m *MockRepository) GetReceivedTransfers(arg0 context.Context, arg1 uuid.UUID) ([]models.ReceivedTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReceivedTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]models.ReceivedTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceivedTransfers indicates an expected call of GetReceivedTransfers.
+func (mr *MockRepositoryMockRecorder) GetReceivedTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceivedTransfers", reflect.TypeOf((*MockRepository)(nil).GetReceivedTransfers), arg0, arg1)
+}
+
+// GetRefreshTokenByHash mocks base method.
+func (m *MockRepository) GetRefreshTokenByHash(arg0 context.Context, arg1 string) (*models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshTokenByHash", arg0, arg1)
+	ret0, _ := ret[0].(*models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshTokenByHash indicates an expected call of GetRefreshTokenByHash.
+func (mr *MockRepositoryMockRecorder) GetRefreshTokenByHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshTokenByHash", reflect.TypeOf((*MockRepository)(nil).GetRefreshTokenByHash), arg0, arg1)
+}
+
+// GetRefundRequests mocks base method.
+func (m *MockRepository) GetRefundRequests(arg0 context.Context) ([]models.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefundRequests", arg0)
+	ret0, _ := ret[0].([]models.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefundRequests indicates an expected call of GetRefundRequests.
+func (mr *MockRepositoryMockRecorder) GetRefundRequests(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefundRequests", reflect.TypeOf((*MockRepository)(nil).GetRefundRequests), arg0)
+}
+
+// GetRefundsByUserID mocks base method.
+func (m *MockRepository) GetRefundsByUserID(arg0 context.Context, arg1 uuid.UUID) ([]models.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefundsByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]models.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefundsByUserID indicates an expected call of GetRefundsByUserID.
+func (mr *MockRepositoryMockRecorder) GetRefundsByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefundsByUserID", reflect.TypeOf((*MockRepository)(nil).GetRefundsByUserID), arg0, arg1)
+}
+
+// GetSentTransfers mocks base method.
+func (m *MockRepository) GetSentTransfers(arg0 context.Context, arg1 uuid.UUID) ([]models.SentTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSentTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]models.SentTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSentTransfers indicates an expected call of GetSentTransfers.
+func (mr *MockRepositoryMockRecorder) GetSentTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSentTransfers", reflect.TypeOf((*MockRepository)(nil).GetSentTransfers), arg0, arg1)
+}
+
+// GetStockMovements mocks base method.
+func (m *MockRepository) GetStockMovements(arg0 context.Context, arg1 string) ([]models.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockMovements", arg0, arg1)
+	ret0, _ := ret[0].([]models.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockMovements indicates an expected call of GetStockMovements.
+func (mr *MockRepositoryMockRecorder) GetStockMovements(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockMovements", reflect.TypeOf((*MockRepository)(nil).GetStockMovements), arg0, arg1)
+}
+
+// GetTransactionsByUserID mocks base method.
+func (m *MockRepository) GetTransactionsByUserID(arg0 context.Context, arg1 uuid.UUID) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByUserID indicates an expected call of GetTransactionsByUserID.
+func (mr *MockRepositoryMockRecorder) GetTransactionsByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByUserID", reflect.TypeOf((*MockRepository)(nil).GetTransactionsByUserID), arg0, arg1)
+}
+
+// GetUserByID mocks base method.
+func (m *MockRepository) GetUserByID(arg0 context.Context, arg1 uuid.UUID) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockRepositoryMockRecorder) GetUserByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockRepository)(nil).GetUserByID), arg0, arg1)
+}
+
+// GetUserByUsername mocks base method.
+func (m *MockRepository) GetUserByUsername(arg0 context.Context, arg1 string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByUsername", arg0, arg1)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByUsername indicates an expected call of GetUserByUsername.
+func (mr *MockRepositoryMockRecorder) GetUserByUsername(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockRepository)(nil).GetUserByUsername), arg0, arg1)
+}
+
+// GrantBonus mocks base method.
+func (m *MockRepository) GrantBonus(arg0 context.Context, arg1 uuid.UUID, arg2 int, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantBonus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantBonus indicates an expected call of GrantBonus.
+func (mr *MockRepositoryMockRecorder) GrantBonus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantBonus", reflect.TypeOf((*MockRepository)(nil).GrantBonus), arg0, arg1, arg2, arg3)
+}
+
+// GrantCoins mocks base method.
+func (m *MockRepository) GrantCoins(arg0 context.Context, arg1, arg2, arg3 uuid.UUID, arg4 int, arg5 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantCoins", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GrantCoins indicates an expected call of GrantCoins.
+func (mr *MockRepositoryMockRecorder) GrantCoins(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantCoins", reflect.TypeOf((*MockRepository)(nil).GrantCoins), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// IsAccessTokenRevoked mocks base method.
+func (m *MockRepository) IsAccessTokenRevoked(arg0 context.Context, arg1 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAccessTokenRevoked", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAccessTokenRevoked indicates an expected call of IsAccessTokenRevoked.
+func (mr *MockRepositoryMockRecorder) IsAccessTokenRevoked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAccessTokenRevoked", reflect.TypeOf((*MockRepository)(nil).IsAccessTokenRevoked), arg0, arg1)
+}
+
+// ListItems mocks base method.
+func (m *MockRepository) ListItems(arg0 context.Context, arg1 models.ItemFilter) ([]models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItems", arg0, arg1)
+	ret0, _ := ret[0].([]models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItems indicates an expected call of ListItems.
+func (mr *MockRepositoryMockRecorder) ListItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItems", reflect.TypeOf((*MockRepository)(nil).ListItems), arg0, arg1)
+}
+
+// ListOrders mocks base method.
+func (m *MockRepository) ListOrders(arg0 context.Context, arg1 string) ([]models.FulfillmentOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", arg0, arg1)
+	ret0, _ := ret[0].([]models.FulfillmentOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockRepositoryMockRecorder) ListOrders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockRepository)(nil).ListOrders), arg0, arg1)
+}
+
+// LockUser mocks base method.
+func (m *MockRepository) LockUser(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockUser indicates an expected call of LockUser.
+func (mr *MockRepositoryMockRecorder) LockUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUser", reflect.TypeOf((*MockRepository)(nil).LockUser), arg0, arg1)
+}
+
+// MarkRefreshTokenUsed mocks base method.
+func (m *MockRepository) MarkRefreshTokenUsed(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRefreshTokenUsed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRefreshTokenUsed indicates an expected call of MarkRefreshTokenUsed.
+func (mr *MockRepositoryMockRecorder) MarkRefreshTokenUsed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRefreshTokenUsed", reflect.TypeOf((*MockRepository)(nil).MarkRefreshTokenUsed), arg0, arg1)
+}
+
+// RefundPurchase mocks base method.
+func (m *MockRepository) RefundPurchase(arg0 context.Context, arg1 *models.Purchase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundPurchase", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefundPurchase indicates an expected call of RefundPurchase.
+func (mr *MockRepositoryMockRecorder) RefundPurchase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundPurchase", reflect.TypeOf((*MockRepository)(nil).RefundPurchase), arg0, arg1)
+}
+
+// RemoveFromCart mocks base method.
+func (m *MockRepository) RemoveFromCart(arg0 context.Context, arg1 uuid.UUID, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromCart", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromCart indicates an expected call of RemoveFromCart.
+func (mr *MockRepositoryMockRecorder) RemoveFromCart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromCart", reflect.TypeOf((*MockRepository)(nil).RemoveFromCart), arg0, arg1, arg2)
+}
+
+// RenameItem mocks base method.
+func (m *MockRepository) RenameItem(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameItem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameItem indicates an expected call of RenameItem.
+func (mr *MockRepositoryMockRecorder) RenameItem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameItem", reflect.TypeOf((*MockRepository)(nil).RenameItem), arg0, arg1, arg2)
+}
+
+// RequestRefund mocks base method.
+func (m *MockRepository) RequestRefund(arg0 context.Context, arg1 *models.Purchase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestRefund", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestRefund indicates an expected call of RequestRefund.
+func (mr *MockRepositoryMockRecorder) RequestRefund(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestRefund", reflect.TypeOf((*MockRepository)(nil).RequestRefund), arg0, arg1)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockRepository) ReserveIdempotencyKey(arg0 context.Context, arg1 uuid.UUID, arg2, arg3 string, arg4 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockRepositoryMockRecorder) ReserveIdempotencyKey(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockRepository)(nil).ReserveIdempotencyKey), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RestockItem mocks base method.
+func (m *MockRepository) RestockItem(arg0 context.Context, arg1 string, arg2 int, arg3 uuid.UUID, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestockItem", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestockItem indicates an expected call of RestockItem.
+func (mr *MockRepositoryMockRecorder) RestockItem(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestockItem", reflect.TypeOf((*MockRepository)(nil).RestockItem), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RetireItem mocks base method.
+func (m *MockRepository) RetireItem(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireItem", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetireItem indicates an expected call of RetireItem.
+func (mr *MockRepositoryMockRecorder) RetireItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireItem", reflect.TypeOf((*MockRepository)(nil).RetireItem), arg0, arg1)
+}
+
+// RevokeAccessToken mocks base method.
+func (m *MockRepository) RevokeAccessToken(arg0 context.Context, arg1 uuid.UUID, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccessToken", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessToken indicates an expected call of RevokeAccessToken.
+func (mr *MockRepositoryMockRecorder) RevokeAccessToken(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessToken", reflect.TypeOf((*MockRepository)(nil).RevokeAccessToken), arg0, arg1, arg2)
+}
+
+// RevokeRefreshTokenFamily mocks base method.
+func (m *MockRepository) RevokeRefreshTokenFamily(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokenFamily", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokenFamily indicates an expected call of RevokeRefreshTokenFamily.
+func (mr *MockRepositoryMockRecorder) RevokeRefreshTokenFamily(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokenFamily", reflect.TypeOf((*MockRepository)(nil).RevokeRefreshTokenFamily), arg0, arg1)
+}
+
+// SaveIdempotentResponse mocks base method.
+func (m *MockRepository) SaveIdempotentResponse(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 *models.IdempotentResponse, arg4 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotentResponse", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotentResponse indicates an expected call of SaveIdempotentResponse.
+func (mr *MockRepositoryMockRecorder) SaveIdempotentResponse(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotentResponse", reflect.TypeOf((*MockRepository)(nil).SaveIdempotentResponse), arg0, arg1, arg2, arg3, arg4)
+}
+
+// SendCoins mocks base method.
+func (m *MockRepository) SendCoins(arg0 context.Context, arg1, arg2 uuid.UUID, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendCoins", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendCoins indicates an expected call of SendCoins.
+func (mr *MockRepositoryMockRecorder) SendCoins(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendCoins", reflect.TypeOf((*MockRepository)(nil).SendCoins), arg0, arg1, arg2, arg3)
+}
+
+// SetItemPurchaseLimit mocks base method.
+func (m *MockRepository) SetItemPurchaseLimit(arg0 context.Context, arg1 string, arg2 *int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetItemPurchaseLimit", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetItemPurchaseLimit indicates an expected call of SetItemPurchaseLimit.
+func (mr *MockRepositoryMockRecorder) SetItemPurchaseLimit(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetItemPurchaseLimit", reflect.TypeOf((*MockRepository)(nil).SetItemPurchaseLimit), arg0, arg1, arg2)
+}
+
+// SetItemStock mocks base method.
+func (m *MockRepository) SetItemStock(arg0 context.Context, arg1 string, arg2 *int, arg3 uuid.UUID, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetItemStock", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetItemStock indicates an expected call of SetItemStock.
+func (mr *MockRepositoryMockRecorder) SetItemStock(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetItemStock", reflect.TypeOf((*MockRepository)(nil).SetItemStock), arg0, arg1, arg2, arg3, arg4)
+}
+
+// UpdateItemPrice mocks base method.
+func (m *MockRepository) UpdateItemPrice(arg0 context.Context, arg1 string, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemPrice", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItemPrice indicates an expected call of UpdateItemPrice.
+func (mr *MockRepositoryMockRecorder) UpdateItemPrice(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemPrice", reflect.TypeOf((*MockRepository)(nil).UpdateItemPrice), arg0, arg1, arg2)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockRepository) UpdateOrderStatus(arg0 context.Context, arg1 *models.FulfillmentOrder, arg2 string, arg3 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockRepositoryMockRecorder) UpdateOrderStatus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockRepository)(nil).UpdateOrderStatus), arg0, arg1, arg2, arg3)
+}
+
+// UpdateUserCoins mocks base method.
+func (m *MockRepository) UpdateUserCoins(arg0 context.Context, arg1 uuid.UUID, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserCoins", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserCoins indicates an expected call of UpdateUserCoins.
+func (mr *MockRepositoryMockRecorder) UpdateUserCoins(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserCoins", reflect.TypeOf((*MockRepository)(nil).UpdateUserCoins), arg0, arg1, arg2)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockRepository) UpdateUserRole(arg0 context.Context, arg1 uuid.UUID, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockRepositoryMockRecorder) UpdateUserRole(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockRepository)(nil).UpdateUserRole), arg0, arg1, arg2)
+}
+
+// WithinTx mocks base method.
+func (m *MockRepository) WithinTx(arg0 context.Context, arg1 func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockRepositoryMockRecorder) WithinTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockRepository)(nil).WithinTx), arg0, arg1)
+}
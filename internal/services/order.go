@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tracing"
+
+	"github.com/google/uuid"
+)
+
+// AdvanceOrder переводит заказ в следующий статус выдачи. Допустимые переходы задает models.CanTransitionOrder.
+// При отмене пользователю возвращаются монеты за все еще не возвращенные покупки заказа, а товар - в остаток.
+func (s *Service) AdvanceOrder(
+	ctx context.Context,
+	actorID, orderID uuid.UUID,
+	to string,
+) (_ *models.FulfillmentOrder, err error) {
+	ctx, span := tracing.Start(ctx, "Service.AdvanceOrder")
+	defer tracing.End(span, &err)
+
+	var order *models.FulfillmentOrder
+	err = s.repo.WithinTx(ctx, func(ctx context.Context) error {
+		o, err := s.repo.GetOrderByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		order = o
+
+		if order == nil {
+			return ErrOrderNotFound
+		}
+		if !models.CanTransitionOrder(order.Status, to) {
+			return ErrInvalidOrderTransition
+		}
+
+		if to == models.OrderCancelled {
+			purchases, err := s.repo.GetPurchasesByOrderID(ctx, orderID)
+			if err != nil {
+				return err
+			}
+			for i := range purchases {
+				if purchases[i].Status == models.PurchaseRefunded {
+					continue
+				}
+				if err := s.repo.RefundPurchase(ctx, &purchases[i]); err != nil {
+					return err
+				}
+			}
+		}
+
+		return s.repo.UpdateOrderStatus(ctx, order, to, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// ListOrders возвращает заказы в указанном статусе; пустой статус - все заказы.
+func (s *Service) ListOrders(ctx context.Context, status string) (_ []models.FulfillmentOrder, err error) {
+	ctx, span := tracing.Start(ctx, "Service.ListOrders")
+	defer tracing.End(span, &err)
+
+	if status != "" && !models.IsValidOrderStatus(status) {
+		return nil, ErrInvalidOrderStatus
+	}
+
+	return s.repo.ListOrders(ctx, status)
+}
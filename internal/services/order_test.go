@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_AdvanceOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	actorID := uuid.New()
+	orderID := uuid.New()
+	order := func(status string) *models.FulfillmentOrder {
+		return &models.FulfillmentOrder{ID: orderID, Status: status}
+	}
+
+	withinTx := func() {
+		mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	tests := []struct {
+		name        string
+		setup       func()
+		to          string
+		expectedErr error
+	}{
+		{
+			name: "placed order is packed",
+			setup: func() {
+				withinTx()
+				o := order(models.OrderPlaced)
+				mockRepo.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(o, nil)
+				mockRepo.EXPECT().UpdateOrderStatus(gomock.Any(), o, models.OrderPacked, actorID).Return(nil)
+			},
+			to: models.OrderPacked,
+		},
+		{
+			name: "placed order cannot be delivered",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order(models.OrderPlaced), nil)
+			},
+			to:          models.OrderDelivered,
+			expectedErr: ErrInvalidOrderTransition,
+		},
+		{
+			name: "delivered order cannot be cancelled",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order(models.OrderDelivered), nil)
+			},
+			to:          models.OrderCancelled,
+			expectedErr: ErrInvalidOrderTransition,
+		},
+		{
+			name: "order not found",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(nil, nil)
+			},
+			to:          models.OrderPacked,
+			expectedErr: ErrOrderNotFound,
+		},
+		{
+			name: "cancellation refunds purchases",
+			setup: func() {
+				withinTx()
+				o := order(models.OrderPacked)
+				mockRepo.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(o, nil)
+				mockRepo.EXPECT().GetPurchasesByOrderID(gomock.Any(), orderID).Return([]models.Purchase{
+					{ID: uuid.New(), OrderID: orderID, Item: "cup", Price: 20, Status: models.PurchaseCompleted},
+					{ID: uuid.New(), OrderID: orderID, Item: "cup", Price: 20, Status: models.PurchaseRefunded},
+					{ID: uuid.New(), OrderID: orderID, Item: "pen", Price: 10, Status: models.PurchaseRefundRequested},
+				}, nil)
+				mockRepo.EXPECT().RefundPurchase(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				mockRepo.EXPECT().UpdateOrderStatus(gomock.Any(), o, models.OrderCancelled, actorID).Return(nil)
+			},
+			to: models.OrderCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			_, err := service.AdvanceOrder(context.Background(), actorID, orderID, tt.to)
+
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
@@ -2,51 +2,86 @@ package services
 
 import (
 	"context"
-	"errors"
 
+	"github.com/derticom/merch-store/internal/metrics"
 	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tracing"
 
 	"github.com/google/uuid"
 )
 
-func (s *Service) BuyItem(ctx context.Context, userID uuid.UUID, itemName string) error {
-	// Получаем данные пользователя и товара
-	user, err := s.repo.GetUserByID(ctx, userID)
-	if err != nil {
+// MaxOrderQuantity ограничивает число единиц товара в одной строке заказа или корзины: каждая единица
+// - отдельная запись о покупке, создаваемая под блокировкой пользователя.
+const MaxOrderQuantity = 100
+
+func (s *Service) BuyItem(ctx context.Context, userID uuid.UUID, itemName string) (err error) {
+	ctx, span := tracing.Start(ctx, "Service.BuyItem")
+	defer tracing.End(span, &err)
+
+	// Проверка баланса, списание монет и создание покупки выполняются атомарно на уровне хранилища
+	if err := s.repo.BuyItem(ctx, userID, itemName); err != nil {
+		metrics.FailedPurchasesTotal.WithLabelValues(failureReason(err)).Inc()
 		return err
 	}
-	if user == nil {
-		return errors.New("user not found")
+
+	metrics.PurchasesTotal.WithLabelValues(itemName).Inc()
+
+	return nil
+}
+
+// BuyItems покупает несколько единиц одного товара в одной транзакции.
+func (s *Service) BuyItems(
+	ctx context.Context,
+	userID uuid.UUID,
+	itemName string,
+	quantity int,
+) (_ *models.Order, err error) {
+	ctx, span := tracing.Start(ctx, "Service.BuyItems")
+	defer tracing.End(span, &err)
+
+	if err := validateQuantity(quantity); err != nil {
+		return nil, err
 	}
 
-	item, err := s.repo.GetItemByName(ctx, itemName)
+	order, err := s.repo.BuyItems(ctx, userID, []models.OrderLine{{Item: itemName, Quantity: quantity}})
 	if err != nil {
-		return err
-	}
-	if item == nil {
-		return errors.New("item not found")
+		metrics.FailedPurchasesTotal.WithLabelValues(failureReason(err)).Inc()
+		return nil, err
 	}
 
-	// Проверяем, что у пользователя достаточно монет
-	if user.Coins < item.Price {
-		return errors.New("insufficient coins")
-	}
+	recordPurchases(order)
 
-	// Обновляем баланс пользователя
-	if err := s.repo.UpdateUserCoins(ctx, userID, user.Coins-item.Price); err != nil {
-		return err
-	}
+	return order, nil
+}
 
-	// Создаем запись о покупке
-	purchase := &models.Purchase{
-		ID:     uuid.New(),
-		UserID: userID,
-		Item:   itemName,
+func validateQuantity(quantity int) error {
+	switch {
+	case quantity <= 0:
+		return ErrNonPositiveQuantity
+	case quantity > MaxOrderQuantity:
+		return ErrQuantityTooLarge
+	default:
+		return nil
 	}
+}
 
-	return s.repo.CreatePurchase(ctx, purchase)
+// recordPurchases учитывает купленные единицы в метриках.
+func recordPurchases(order *models.Order) {
+	for _, purchase := range order.Purchases {
+		metrics.PurchasesTotal.WithLabelValues(purchase.Item).Inc()
+	}
 }
 
-func (s *Service) GetPurchaseHistory(ctx context.Context, userID uuid.UUID) ([]models.Purchase, error) {
+func (s *Service) GetPurchaseHistory(ctx context.Context, userID uuid.UUID) (_ []models.Purchase, err error) {
+	ctx, span := tracing.Start(ctx, "Service.GetPurchaseHistory")
+	defer tracing.End(span, &err)
+
 	return s.repo.GetPurchasesByUserID(ctx, userID)
 }
+
+func (s *Service) GetInventory(ctx context.Context, userID uuid.UUID) (_ []models.InventoryItem, err error) {
+	ctx, span := tracing.Start(ctx, "Service.GetInventory")
+	defer tracing.End(span, &err)
+
+	return s.repo.GetInventoryByUserID(ctx, userID)
+}
@@ -22,14 +22,6 @@ func TestService_BuyItem(t *testing.T) {
 
 	userID := uuid.New()
 	itemName := "test-item"
-	user := &models.User{
-		ID:    userID,
-		Coins: 100,
-	}
-	item := &models.Item{
-		Name:  itemName,
-		Price: 50,
-	}
 
 	tests := []struct {
 		name        string
@@ -41,10 +33,7 @@ func TestService_BuyItem(t *testing.T) {
 		{
 			name: "successful purchase",
 			setup: func() {
-				mockRepo.EXPECT().GetUserByID(gomock.Any(), userID).Return(user, nil)
-				mockRepo.EXPECT().GetItemByName(gomock.Any(), itemName).Return(item, nil)
-				mockRepo.EXPECT().UpdateUserCoins(gomock.Any(), userID, user.Coins-item.Price).Return(nil)
-				mockRepo.EXPECT().CreatePurchase(gomock.Any(), gomock.Any()).Return(nil)
+				mockRepo.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(nil)
 			},
 			userID:      userID,
 			itemName:    itemName,
@@ -53,65 +42,155 @@ func TestService_BuyItem(t *testing.T) {
 		{
 			name: "user not found",
 			setup: func() {
-				mockRepo.EXPECT().GetUserByID(gomock.Any(), userID).Return(nil, nil)
+				mockRepo.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(ErrUserNotFound)
 			},
 			userID:      userID,
 			itemName:    itemName,
-			expectedErr: errors.New("user not found"),
+			expectedErr: ErrUserNotFound,
 		},
 		{
 			name: "item not found",
 			setup: func() {
-				mockRepo.EXPECT().GetUserByID(gomock.Any(), userID).Return(user, nil)
-				mockRepo.EXPECT().GetItemByName(gomock.Any(), itemName).Return(nil, nil)
+				mockRepo.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(ErrItemNotFound)
 			},
 			userID:      userID,
 			itemName:    itemName,
-			expectedErr: errors.New("item not found"),
+			expectedErr: ErrItemNotFound,
 		},
 		{
 			name: "insufficient coins",
 			setup: func() {
-				mockRepo.EXPECT().GetUserByID(gomock.Any(), userID).Return(user, nil)
-				mockRepo.EXPECT().GetItemByName(gomock.Any(), itemName).Return(&models.Item{Name: itemName, Price: 200}, nil)
+				mockRepo.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(ErrInsufficientCoins)
 			},
 			userID:      userID,
 			itemName:    itemName,
-			expectedErr: errors.New("insufficient coins"),
+			expectedErr: ErrInsufficientCoins,
 		},
 		{
-			name: "error updating user coins",
+			name: "error in repository",
 			setup: func() {
-				mockRepo.EXPECT().GetUserByID(gomock.Any(), userID).Return(user, nil)
-				mockRepo.EXPECT().GetItemByName(gomock.Any(), itemName).Return(item, nil)
-				mockRepo.EXPECT().UpdateUserCoins(gomock.Any(), userID, user.Coins-item.Price).Return(errors.New("update error"))
+				mockRepo.EXPECT().BuyItem(gomock.Any(), userID, itemName).Return(errors.New("purchase error"))
 			},
 			userID:      userID,
 			itemName:    itemName,
-			expectedErr: errors.New("update error"),
+			expectedErr: errors.New("purchase error"),
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			err := service.BuyItem(context.Background(), tt.userID, tt.itemName)
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestService_BuyItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	userID := uuid.New()
+	order := &models.Order{Purchases: []models.Purchase{{Item: "cup", Price: 20}, {Item: "cup", Price: 20}}, Total: 40}
+
+	tests := []struct {
+		name        string
+		setup       func()
+		quantity    int
+		expected    *models.Order
+		expectedErr error
+	}{
 		{
-			name: "error creating purchase",
+			name: "successful purchase",
 			setup: func() {
-				mockRepo.EXPECT().GetUserByID(gomock.Any(), userID).Return(user, nil)
-				mockRepo.EXPECT().GetItemByName(gomock.Any(), itemName).Return(item, nil)
-				mockRepo.EXPECT().UpdateUserCoins(gomock.Any(), userID, user.Coins-item.Price).Return(nil)
-				mockRepo.EXPECT().CreatePurchase(gomock.Any(), gomock.Any()).Return(errors.New("purchase error"))
+				mockRepo.EXPECT().BuyItems(gomock.Any(), userID, []models.OrderLine{{Item: "cup", Quantity: 2}}).
+					Return(order, nil)
+			},
+			quantity: 2,
+			expected: order,
+		},
+		{
+			name:        "non-positive quantity",
+			setup:       func() {},
+			quantity:    0,
+			expectedErr: ErrNonPositiveQuantity,
+		},
+		{
+			name:        "quantity above limit",
+			setup:       func() {},
+			quantity:    MaxOrderQuantity + 1,
+			expectedErr: ErrQuantityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			result, err := service.BuyItems(context.Background(), userID, "cup", tt.quantity)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestService_GetInventory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	userID := uuid.New()
+	inventory := []models.InventoryItem{
+		{Type: "cup", Quantity: 2},
+		{Type: "pen", Quantity: 1},
+	}
+
+	tests := []struct {
+		name        string
+		setup       func()
+		userID      uuid.UUID
+		expected    []models.InventoryItem
+		expectedErr error
+	}{
+		{
+			name: "successful get inventory",
+			setup: func() {
+				mockRepo.EXPECT().GetInventoryByUserID(gomock.Any(), userID).Return(inventory, nil)
 			},
 			userID:      userID,
-			itemName:    itemName,
-			expectedErr: errors.New("purchase error"),
+			expected:    inventory,
+			expectedErr: nil,
+		},
+		{
+			name: "error getting inventory",
+			setup: func() {
+				mockRepo.EXPECT().GetInventoryByUserID(gomock.Any(), userID).Return(nil, errors.New("repository error"))
+			},
+			userID:      userID,
+			expected:    nil,
+			expectedErr: errors.New("repository error"),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			err := service.BuyItem(context.Background(), tt.userID, tt.itemName)
+			result, err := service.GetInventory(context.Background(), tt.userID)
 			if tt.expectedErr != nil {
 				assert.EqualError(t, err, tt.expectedErr.Error())
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
 			}
 		})
 	}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tracing"
+)
+
+// reconcileNote - комментарий корректирующих проводок, записанных при сверке.
+const reconcileNote = "reconcile correction"
+
+// Reconcile пересчитывает ожидаемый баланс каждого пользователя по начислениям, переводам и покупкам
+// и сравнивает его с фактическим. С fix для каждого расхождения записывается корректирующая проводка,
+// приводящая баланс к ожидаемому; перед записью расхождение перепроверяется под блокировкой пользователя,
+// чтобы не исправить баланс, изменившийся после первого чтения.
+func (s *Service) Reconcile(ctx context.Context, fix bool) (_ *models.ReconcileReport, err error) {
+	ctx, span := tracing.Start(ctx, "Service.Reconcile")
+	defer tracing.End(span, &err)
+
+	balances, err := s.repo.GetBalanceReconciliations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ReconcileReport{
+		Checked:    len(balances),
+		Mismatches: make([]models.BalanceReconciliation, 0),
+		Fixed:      fix,
+	}
+	for _, balance := range balances {
+		if balance.Difference() != 0 {
+			report.Mismatches = append(report.Mismatches, balance)
+		}
+	}
+
+	if !fix {
+		return report, nil
+	}
+
+	for i := range report.Mismatches {
+		mismatch := &report.Mismatches[i]
+		err := s.repo.WithinTx(ctx, func(ctx context.Context) error {
+			current, err := s.repo.GetBalanceReconciliation(ctx, mismatch.UserID)
+			if err != nil {
+				return err
+			}
+			*mismatch = *current
+			if current.Difference() == 0 {
+				return nil
+			}
+
+			return s.repo.AdjustUserBalance(ctx, current.UserID, current.Difference(), reconcileNote)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
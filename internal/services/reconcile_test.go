@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Reconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	alice := models.BalanceReconciliation{
+		UserID: uuid.New(), Username: "alice", Grants: 1000, Sent: 100, Purchases: 20, Expected: 880, Actual: 880,
+	}
+	bob := models.BalanceReconciliation{
+		UserID: uuid.New(), Username: "bob", Grants: 1000, Received: 100, Expected: 1100, Actual: 1050,
+	}
+	fixedBob := bob
+	fixedBob.Actual = 1100
+
+	withinTx := func() {
+		mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	tests := []struct {
+		name     string
+		setup    func()
+		fix      bool
+		expected *models.ReconcileReport
+	}{
+		{
+			name: "mismatches are reported",
+			setup: func() {
+				mockRepo.EXPECT().GetBalanceReconciliations(gomock.Any()).
+					Return([]models.BalanceReconciliation{alice, bob}, nil)
+			},
+			expected: &models.ReconcileReport{Checked: 2, Mismatches: []models.BalanceReconciliation{bob}},
+		},
+		{
+			name: "mismatches are fixed",
+			setup: func() {
+				mockRepo.EXPECT().GetBalanceReconciliations(gomock.Any()).
+					Return([]models.BalanceReconciliation{alice, bob}, nil)
+				withinTx()
+				mockRepo.EXPECT().GetBalanceReconciliation(gomock.Any(), bob.UserID).Return(&bob, nil)
+				mockRepo.EXPECT().AdjustUserBalance(gomock.Any(), bob.UserID, 50, reconcileNote).Return(nil)
+			},
+			fix:      true,
+			expected: &models.ReconcileReport{Checked: 2, Mismatches: []models.BalanceReconciliation{bob}, Fixed: true},
+		},
+		{
+			name: "balance fixed concurrently is not adjusted",
+			setup: func() {
+				mockRepo.EXPECT().GetBalanceReconciliations(gomock.Any()).
+					Return([]models.BalanceReconciliation{bob}, nil)
+				withinTx()
+				mockRepo.EXPECT().GetBalanceReconciliation(gomock.Any(), bob.UserID).Return(&fixedBob, nil)
+			},
+			fix:      true,
+			expected: &models.ReconcileReport{Checked: 1, Mismatches: []models.BalanceReconciliation{fixedBob}, Fixed: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			report, err := service.Reconcile(context.Background(), tt.fix)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, report)
+		})
+	}
+}
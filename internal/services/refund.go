@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tracing"
+
+	"github.com/google/uuid"
+)
+
+// RequestRefund создает заявку на возврат покупки. Заявку можно подать только на свою покупку,
+// только в течение окна возврата и только пока заказ не выдан; монеты возвращаются после
+// одобрения администратором.
+func (s *Service) RequestRefund(ctx context.Context, userID, purchaseID uuid.UUID) (_ *models.Purchase, err error) {
+	ctx, span := tracing.Start(ctx, "Service.RequestRefund")
+	defer tracing.End(span, &err)
+
+	var purchase *models.Purchase
+	err = s.repo.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.repo.GetPurchaseByID(ctx, purchaseID)
+		if err != nil {
+			return err
+		}
+		purchase = p
+
+		// Чужая покупка неотличима от несуществующей.
+		if purchase == nil || purchase.UserID != userID {
+			return ErrPurchaseNotFound
+		}
+		if purchase.Status != models.PurchaseCompleted {
+			return ErrRefundNotAllowed
+		}
+		if time.Since(purchase.CreatedAt) > s.refundWindow {
+			return ErrRefundWindowExpired
+		}
+		if _, err := s.refundableOrder(ctx, purchase); err != nil {
+			return err
+		}
+
+		return s.repo.RequestRefund(ctx, purchase)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return purchase, nil
+}
+
+// ApproveRefund одобряет заявку на возврат: пользователь получает назад цену покупки, а товар
+// возвращается в остаток. Если в заказе не осталось невозвращенных позиций, заказ отменяется
+// от имени администратора.
+func (s *Service) ApproveRefund(ctx context.Context, adminID, purchaseID uuid.UUID) (_ *models.Purchase, err error) {
+	ctx, span := tracing.Start(ctx, "Service.ApproveRefund")
+	defer tracing.End(span, &err)
+
+	var purchase *models.Purchase
+	err = s.repo.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.repo.GetPurchaseByID(ctx, purchaseID)
+		if err != nil {
+			return err
+		}
+		purchase = p
+
+		if purchase == nil {
+			return ErrPurchaseNotFound
+		}
+		if purchase.Status != models.PurchaseRefundRequested {
+			return ErrRefundNotRequested
+		}
+
+		// Заказ могли выдать уже после подачи заявки.
+		order, err := s.refundableOrder(ctx, purchase)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repo.RefundPurchase(ctx, purchase); err != nil {
+			return err
+		}
+
+		return s.cancelFullyRefundedOrder(ctx, adminID, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return purchase, nil
+}
+
+// refundableOrder блокирует заказ покупки и проверяет, что он еще не выдан: выданный товар
+// уже у пользователя, и вернуть его в остаток нельзя.
+func (s *Service) refundableOrder(ctx context.Context, purchase *models.Purchase) (*models.FulfillmentOrder, error) {
+	order, err := s.repo.GetOrderByID(ctx, purchase.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.Status == models.OrderDelivered {
+		return nil, ErrRefundOrderDelivered
+	}
+
+	return order, nil
+}
+
+// cancelFullyRefundedOrder отменяет заказ, в котором не осталось невозвращенных позиций,
+// чтобы склад не собирал пустой заказ.
+func (s *Service) cancelFullyRefundedOrder(
+	ctx context.Context,
+	actorID uuid.UUID,
+	order *models.FulfillmentOrder,
+) error {
+	purchases, err := s.repo.GetPurchasesByOrderID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	for _, p := range purchases {
+		if p.Status != models.PurchaseRefunded {
+			return nil
+		}
+	}
+	if !models.CanTransitionOrder(order.Status, models.OrderCancelled) {
+		return nil
+	}
+
+	return s.repo.UpdateOrderStatus(ctx, order, models.OrderCancelled, actorID)
+}
+
+func (s *Service) GetRefundRequests(ctx context.Context) (_ []models.Purchase, err error) {
+	ctx, span := tracing.Start(ctx, "Service.GetRefundRequests")
+	defer tracing.End(span, &err)
+
+	return s.repo.GetRefundRequests(ctx)
+}
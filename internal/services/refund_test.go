@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/services/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_RequestRefund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo, WithRefundWindow(24*time.Hour))
+
+	userID := uuid.New()
+	purchaseID := uuid.New()
+	orderID := uuid.New()
+	purchase := func(status string, age time.Duration) *models.Purchase {
+		return &models.Purchase{
+			ID:        purchaseID,
+			UserID:    userID,
+			OrderID:   orderID,
+			Item:      "cup",
+			Price:     20,
+			Status:    status,
+			CreatedAt: time.Now().Add(-age),
+		}
+	}
+
+	withinTx := func() {
+		mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	tests := []struct {
+		name        string
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name: "refund requested",
+			setup: func() {
+				withinTx()
+				p := purchase(models.PurchaseCompleted, time.Hour)
+				mockRepo.EXPECT().GetPurchaseByID(gomock.Any(), purchaseID).Return(p, nil)
+				mockRepo.EXPECT().GetOrderByID(gomock.Any(), orderID).
+					Return(&models.FulfillmentOrder{ID: orderID, Status: models.OrderPacked}, nil)
+				mockRepo.EXPECT().RequestRefund(gomock.Any(), p).Return(nil)
+			},
+		},
+		{
+			name: "order delivered",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetPurchaseByID(gomock.Any(), purchaseID).
+					Return(purchase(models.PurchaseCompleted, time.Hour), nil)
+				mockRepo.EXPECT().GetOrderByID(gomock.Any(), orderID).
+					Return(&models.FulfillmentOrder{ID: orderID, Status: models.OrderDelivered}, nil)
+			},
+			expectedErr: ErrRefundOrderDelivered,
+		},
+		{
+			name: "purchase not found",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetPurchaseByID(gomock.Any(), purchaseID).Return(nil, nil)
+			},
+			expectedErr: ErrPurchaseNotFound,
+		},
+		{
+			name: "purchase of another user",
+			setup: func() {
+				withinTx()
+				p := purchase(models.PurchaseCompleted, time.Hour)
+				p.UserID = uuid.New()
+				mockRepo.EXPECT().GetPurchaseByID(gomock.Any(), purchaseID).Return(p, nil)
+			},
+			expectedErr: ErrPurchaseNotFound,
+		},
+		{
+			name: "refund already requested",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetPurchaseByID(gomock.Any(), purchaseID).
+					Return(purchase(models.PurchaseRefundRequested, time.Hour), nil)
+			},
+			expectedErr: ErrRefundNotAllowed,
+		},
+		{
+			name: "refund window expired",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetPurchaseByID(gomock.Any(), purchaseID).
+					Return(purchase(models.PurchaseCompleted, 48*time.Hour), nil)
+			},
+			expectedErr: ErrRefundWindowExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			_, err := service.RequestRefund(context.Background(), userID, purchaseID)
+
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestService_ApproveRefund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	adminID := uuid.New()
+	purchaseID := uuid.New()
+	orderID := uuid.New()
+	requested := func() *models.Purchase {
+		return &models.Purchase{
+			ID:      purchaseID,
+			OrderID: orderID,
+			Item:    "cup",
+			Price:   20,
+			Status:  models.PurchaseRefundRequested,
+		}
+	}
+	order := func(status string) *models.FulfillmentOrder {
+		return &models.FulfillmentOrder{ID: orderID, Status: status}
+	}
+
+	withinTx := func() {
+		mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	tests := []struct {
+		name        string
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name: "refund approved",
+			setup: func() {
+				withinTx()
+				p := requested()
+				o := order(models.OrderPlaced)
+				mockRepo.EXPECT().GetPurchaseByID(gomock.Any(), purchaseID).Return(p, nil)
+				mockRepo.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(o, nil)
+				mockRepo.EXPECT().RefundPurchase(gomock.Any(), p).Return(nil)
+				mockRepo.EXPECT().GetPurchasesByOrderID(gomock.Any(), orderID).Return([]models.Purchase{
+					{ID: purchaseID, OrderID: orderID, Status: models.PurchaseRefunded},
+					{ID: uuid.New(), OrderID: orderID, Status: models.PurchaseCompleted},
+				}, nil)
+			},
+		},
+		{
+			name: "last line refunded cancels order",
+			setup: func() {
+				withinTx()
+				p := requested()
+				o := order(models.OrderPacked)
+				mockRepo.EXPECT().GetPurchaseByID(gomock.Any(), purchaseID).Return(p, nil)
+				mockRepo.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(o, nil)
+				mockRepo.EXPECT().RefundPurchase(gomock.Any(), p).Return(nil)
+				mockRepo.EXPECT().GetPurchasesByOrderID(gomock.Any(), orderID).Return([]models.Purchase{
+					{ID: purchaseID, OrderID: orderID, Status: models.PurchaseRefunded},
+					{ID: uuid.New(), OrderID: orderID, Status: models.PurchaseRefunded},
+				}, nil)
+				mockRepo.EXPECT().UpdateOrderStatus(gomock.Any(), o, models.OrderCancelled, adminID).Return(nil)
+			},
+		},
+		{
+			name: "order delivered",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetPurchaseByID(gomock.Any(), purchaseID).Return(requested(), nil)
+				mockRepo.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order(models.OrderDelivered), nil)
+			},
+			expectedErr: ErrRefundOrderDelivered,
+		},
+		{
+			name: "purchase not found",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetPurchaseByID(gomock.Any(), purchaseID).Return(nil, nil)
+			},
+			expectedErr: ErrPurchaseNotFound,
+		},
+		{
+			name: "refund not requested",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetPurchaseByID(gomock.Any(), purchaseID).
+					Return(&models.Purchase{ID: purchaseID, Status: models.PurchaseCompleted}, nil)
+			},
+			expectedErr: ErrRefundNotRequested,
+		},
+		{
+			name: "already refunded",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetPurchaseByID(gomock.Any(), purchaseID).
+					Return(&models.Purchase{ID: purchaseID, Status: models.PurchaseRefunded}, nil)
+			},
+			expectedErr: ErrRefundNotRequested,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			_, err := service.ApproveRefund(context.Background(), adminID, purchaseID)
+
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/derticom/merch-store/internal/models"
 
@@ -12,21 +13,151 @@ import (
 type Repository interface {
 	GetAllItems(ctx context.Context) ([]models.Item, error)
 	GetItemByName(ctx context.Context, name string) (*models.Item, error)
+	ListItems(ctx context.Context, filter models.ItemFilter) ([]models.Item, error)
+	CreateItem(ctx context.Context, item *models.Item) error
+	UpdateItemPrice(ctx context.Context, name string, price int) error
+	RenameItem(ctx context.Context, name, newName string) error
+	RetireItem(ctx context.Context, name string) error
+	SetItemPurchaseLimit(ctx context.Context, name string, limit *int) error
+	RestockItem(ctx context.Context, name string, quantity int, adminID uuid.UUID, note string) error
+	SetItemStock(ctx context.Context, name string, stock *int, adminID uuid.UUID, note string) error
+	GetStockMovements(ctx context.Context, name string) ([]models.StockMovement, error)
 	CreatePurchase(ctx context.Context, purchase *models.Purchase) error
 	GetPurchasesByUserID(ctx context.Context, userID uuid.UUID) ([]models.Purchase, error)
+	GetInventoryByUserID(ctx context.Context, userID uuid.UUID) ([]models.InventoryItem, error)
+	GetPurchaseByID(ctx context.Context, id uuid.UUID) (*models.Purchase, error)
+	RequestRefund(ctx context.Context, purchase *models.Purchase) error
+	RefundPurchase(ctx context.Context, purchase *models.Purchase) error
+	GetRefundRequests(ctx context.Context) ([]models.Purchase, error)
+	GetRefundsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Refund, error)
+	GetOrderByID(ctx context.Context, id uuid.UUID) (*models.FulfillmentOrder, error)
+	GetPurchasesByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.Purchase, error)
+	UpdateOrderStatus(ctx context.Context, order *models.FulfillmentOrder, to string, actorID uuid.UUID) error
+	ListOrders(ctx context.Context, status string) ([]models.FulfillmentOrder, error)
+	CheckLedger(ctx context.Context) (*models.LedgerCheck, error)
+	GetBalanceReconciliations(ctx context.Context) ([]models.BalanceReconciliation, error)
+	GetBalanceReconciliation(ctx context.Context, userID uuid.UUID) (*models.BalanceReconciliation, error)
+	AdjustUserBalance(ctx context.Context, userID uuid.UUID, amount int, note string) error
+	GrantCoins(ctx context.Context, batchID, adminID, userID uuid.UUID, amount int, reason string) (int, error)
+	GetGrantsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Grant, error)
+	GrantBonus(ctx context.Context, userID uuid.UUID, amount int, reason string) error
+	BuyItem(ctx context.Context, userID uuid.UUID, itemName string) error
+	BuyItems(ctx context.Context, userID uuid.UUID, lines []models.OrderLine) (*models.Order, error)
+	AddToCart(ctx context.Context, userID uuid.UUID, itemName string, quantity int) error
+	RemoveFromCart(ctx context.Context, userID uuid.UUID, itemName string) error
+	GetCart(ctx context.Context, userID uuid.UUID) ([]models.CartLine, error)
+	ClearCart(ctx context.Context, userID uuid.UUID, lines []models.OrderLine) error
 	CreateTransaction(ctx context.Context, transaction *models.Transaction) error
 	GetTransactionsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Transaction, error)
+	GetReceivedTransfers(ctx context.Context, userID uuid.UUID) ([]models.ReceivedTransfer, error)
+	GetSentTransfers(ctx context.Context, userID uuid.UUID) ([]models.SentTransfer, error)
 	CreateUser(ctx context.Context, user *models.User) error
 	GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	LockUser(ctx context.Context, id uuid.UUID) error
 	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
 	UpdateUserCoins(ctx context.Context, id uuid.UUID, coins int) error
+	UpdateUserRole(ctx context.Context, id uuid.UUID, role string) error
 	SendCoins(ctx context.Context, fromUserID, toUserID uuid.UUID, amount int) error
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAccessToken(ctx context.Context, jti uuid.UUID, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti uuid.UUID) (bool, error)
+	ReserveIdempotencyKey(
+		ctx context.Context,
+		userID uuid.UUID,
+		key, requestHash string,
+		expiresAt time.Time,
+	) (bool, error)
+	GetIdempotentResponse(ctx context.Context, userID uuid.UUID, key string) (*models.IdempotentResponse, error)
+	SaveIdempotentResponse(
+		ctx context.Context,
+		userID uuid.UUID,
+		key string,
+		resp *models.IdempotentResponse,
+		expiresAt time.Time,
+	) error
+	// WithinTx выполняет fn в одной транзакции: все вызовы репозитория с переданным в fn контекстом
+	// либо фиксируются вместе, либо откатываются при ошибке. При ошибках сериализации fn может быть
+	// вызвана повторно.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
+const (
+	defaultRefreshTokenTTL   = 30 * 24 * time.Hour
+	defaultIdempotencyKeyTTL = 24 * time.Hour
+	defaultRefundWindow      = 7 * 24 * time.Hour
+	defaultInitialBalance    = 1000
+)
+
 type Service struct {
-	repo Repository
+	repo              Repository
+	refreshTokenTTL   time.Duration
+	idempotencyKeyTTL time.Duration
+	refundWindow      time.Duration
+	initialBalance    int
+	welcomeBonuses    []models.WelcomeBonus
+}
+
+// Option задает необязательные параметры Service.
+type Option func(s *Service)
+
+// WithRefreshTokenTTL задает время жизни выдаваемых refresh-токенов.
+func WithRefreshTokenTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		if ttl > 0 {
+			s.refreshTokenTTL = ttl
+		}
+	}
+}
+
+// WithIdempotencyKeyTTL задает, сколько хранится ответ на запрос с ключом идемпотентности.
+func WithIdempotencyKeyTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		if ttl > 0 {
+			s.idempotencyKeyTTL = ttl
+		}
+	}
 }
 
-func New(repo Repository) *Service {
-	return &Service{repo: repo}
+// WithRefundWindow задает, сколько времени после покупки пользователь может запросить возврат.
+func WithRefundWindow(window time.Duration) Option {
+	return func(s *Service) {
+		if window > 0 {
+			s.refundWindow = window
+		}
+	}
+}
+
+// WithInitialBalance задает баланс, который получает новый пользователь. Ноль допустим: тогда монеты
+// при регистрации начисляются только приветственными бонусами.
+func WithInitialBalance(balance int) Option {
+	return func(s *Service) {
+		if balance >= 0 {
+			s.initialBalance = balance
+		}
+	}
+}
+
+// WithWelcomeBonuses задает правила приветственных бонусов. Пользователь получает все подходящие бонусы.
+func WithWelcomeBonuses(bonuses ...models.WelcomeBonus) Option {
+	return func(s *Service) {
+		s.welcomeBonuses = bonuses
+	}
+}
+
+func New(repo Repository, opts ...Option) *Service {
+	s := &Service{
+		repo:              repo,
+		refreshTokenTTL:   defaultRefreshTokenTTL,
+		idempotencyKeyTTL: defaultIdempotencyKeyTTL,
+		refundWindow:      defaultRefundWindow,
+		initialBalance:    defaultInitialBalance,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
@@ -2,29 +2,94 @@ package services
 
 import (
 	"context"
-	"errors"
 
+	"github.com/derticom/merch-store/internal/metrics"
 	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tracing"
 
 	"github.com/google/uuid"
 )
 
-func (s *Service) SendCoins(ctx context.Context, fromUserID, toUserID uuid.UUID, amount int) error {
-	if fromUserID == toUserID {
-		return errors.New("cannot send coins to yourself")
-	}
+// SendCoins переводит монеты пользователю, заданному именем или ID.
+func (s *Service) SendCoins(ctx context.Context, fromUserID uuid.UUID, toUser string, amount int) (err error) {
+	ctx, span := tracing.Start(ctx, "Service.SendCoins")
+	defer tracing.End(span, &err)
 
 	if amount <= 0 {
-		return errors.New("amount must be positive")
+		return ErrNonPositiveAmount
 	}
 
-	if err := s.repo.SendCoins(ctx, fromUserID, toUserID, amount); err != nil {
+	err = s.repo.WithinTx(ctx, func(ctx context.Context) error {
+		recipient, err := s.findRecipient(ctx, toUser)
+		if err != nil {
+			return err
+		}
+		if recipient == nil {
+			return ErrRecipientNotFound
+		}
+
+		if fromUserID == recipient.ID {
+			return ErrSelfTransfer
+		}
+
+		return s.repo.SendCoins(ctx, fromUserID, recipient.ID, amount)
+	})
+	if err != nil {
 		return err
 	}
 
+	metrics.CoinsTransferredTotal.Add(float64(amount))
+
 	return nil
 }
 
-func (s *Service) GetTransactionHistory(ctx context.Context, userID uuid.UUID) ([]models.Transaction, error) {
+// findRecipient ищет получателя по ID, если toUser является UUID, иначе по имени пользователя.
+func (s *Service) findRecipient(ctx context.Context, toUser string) (*models.User, error) {
+	if id, err := uuid.Parse(toUser); err == nil {
+		user, err := s.repo.GetUserByID(ctx, id)
+		if err != nil || user != nil {
+			return user, err
+		}
+	}
+
+	return s.repo.GetUserByUsername(ctx, toUser)
+}
+
+func (s *Service) GetTransactionHistory(ctx context.Context, userID uuid.UUID) (_ []models.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "Service.GetTransactionHistory")
+	defer tracing.End(span, &err)
+
 	return s.repo.GetTransactionsByUserID(ctx, userID)
 }
+
+func (s *Service) GetCoinHistory(ctx context.Context, userID uuid.UUID) (_ *models.CoinHistory, err error) {
+	ctx, span := tracing.Start(ctx, "Service.GetCoinHistory")
+	defer tracing.End(span, &err)
+
+	received, err := s.repo.GetReceivedTransfers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := s.repo.GetSentTransfers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.repo.GetRefundsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := s.repo.GetGrantsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CoinHistory{
+		Received: received,
+		Sent:     sent,
+		Refunds:  refunds,
+		Grants:   grants,
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/derticom/merch-store/internal/models"
 	"github.com/derticom/merch-store/internal/services/mocks"
 
 	"github.com/golang/mock/gomock"
@@ -20,50 +21,87 @@ func TestService_SendCoins(t *testing.T) {
 	service := New(mockRepo)
 
 	fromUserID := uuid.New()
-	toUserID := uuid.New()
+	recipient := &models.User{ID: uuid.New(), Username: "recipient"}
 	amount := 50
 
+	withinTx := func() {
+		mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
 	tests := []struct {
 		name        string
 		setup       func()
 		fromUserID  uuid.UUID
-		toUserID    uuid.UUID
+		toUser      string
 		amount      int
 		expectedErr error
 	}{
 		{
-			name: "successful coin transfer",
+			name: "successful coin transfer by username",
 			setup: func() {
-				mockRepo.EXPECT().SendCoins(gomock.Any(), fromUserID, toUserID, amount).Return(nil)
+				withinTx()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), recipient.Username).Return(recipient, nil)
+				mockRepo.EXPECT().SendCoins(gomock.Any(), fromUserID, recipient.ID, amount).Return(nil)
 			},
 			fromUserID:  fromUserID,
-			toUserID:    toUserID,
+			toUser:      recipient.Username,
 			amount:      amount,
 			expectedErr: nil,
 		},
 		{
-			name:        "sender and receiver are the same",
-			setup:       func() {},
+			name: "successful coin transfer by ID",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), recipient.ID).Return(recipient, nil)
+				mockRepo.EXPECT().SendCoins(gomock.Any(), fromUserID, recipient.ID, amount).Return(nil)
+			},
 			fromUserID:  fromUserID,
-			toUserID:    fromUserID,
+			toUser:      recipient.ID.String(),
 			amount:      amount,
-			expectedErr: errors.New("cannot send coins to yourself"),
+			expectedErr: nil,
+		},
+		{
+			name: "recipient not found",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "unknown").Return(nil, nil)
+			},
+			fromUserID:  fromUserID,
+			toUser:      "unknown",
+			amount:      amount,
+			expectedErr: ErrRecipientNotFound,
+		},
+		{
+			name: "sender and receiver are the same",
+			setup: func() {
+				withinTx()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), recipient.Username).Return(recipient, nil)
+			},
+			fromUserID:  recipient.ID,
+			toUser:      recipient.Username,
+			amount:      amount,
+			expectedErr: ErrSelfTransfer,
 		},
 		{
 			name:        "non-positive amount",
 			setup:       func() {},
 			fromUserID:  fromUserID,
-			toUserID:    toUserID,
+			toUser:      recipient.Username,
 			amount:      0,
-			expectedErr: errors.New("amount must be positive"),
+			expectedErr: ErrNonPositiveAmount,
 		},
 		{
 			name: "error in repository",
 			setup: func() {
-				mockRepo.EXPECT().SendCoins(gomock.Any(), fromUserID, toUserID, amount).Return(errors.New("repository error"))
+				withinTx()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), recipient.Username).Return(recipient, nil)
+				mockRepo.EXPECT().SendCoins(gomock.Any(), fromUserID, recipient.ID, amount).Return(errors.New("repository error"))
 			},
 			fromUserID:  fromUserID,
-			toUserID:    toUserID,
+			toUser:      recipient.Username,
 			amount:      amount,
 			expectedErr: errors.New("repository error"),
 		},
@@ -72,11 +110,78 @@ func TestService_SendCoins(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			err := service.SendCoins(context.Background(), tt.fromUserID, tt.toUserID, tt.amount)
+			err := service.SendCoins(context.Background(), tt.fromUserID, tt.toUser, tt.amount)
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestService_GetCoinHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	userID := uuid.New()
+	received := []models.ReceivedTransfer{{FromUser: "alice", Amount: 50}}
+	sent := []models.SentTransfer{{ToUser: "bob", Amount: 100}}
+	refunds := []models.Refund{{Item: "cup", Amount: 20}}
+	grants := []models.Grant{{Amount: 1000, Reason: "initial balance"}}
+
+	tests := []struct {
+		name        string
+		setup       func()
+		userID      uuid.UUID
+		expected    *models.CoinHistory
+		expectedErr error
+	}{
+		{
+			name: "successful get coin history",
+			setup: func() {
+				mockRepo.EXPECT().GetReceivedTransfers(gomock.Any(), userID).Return(received, nil)
+				mockRepo.EXPECT().GetSentTransfers(gomock.Any(), userID).Return(sent, nil)
+				mockRepo.EXPECT().GetRefundsByUserID(gomock.Any(), userID).Return(refunds, nil)
+				mockRepo.EXPECT().GetGrantsByUserID(gomock.Any(), userID).Return(grants, nil)
+			},
+			userID:      userID,
+			expected:    &models.CoinHistory{Received: received, Sent: sent, Refunds: refunds, Grants: grants},
+			expectedErr: nil,
+		},
+		{
+			name: "error getting received transfers",
+			setup: func() {
+				mockRepo.EXPECT().GetReceivedTransfers(gomock.Any(), userID).Return(nil, errors.New("repository error"))
+			},
+			userID:      userID,
+			expected:    nil,
+			expectedErr: errors.New("repository error"),
+		},
+		{
+			name: "error getting sent transfers",
+			setup: func() {
+				mockRepo.EXPECT().GetReceivedTransfers(gomock.Any(), userID).Return(received, nil)
+				mockRepo.EXPECT().GetSentTransfers(gomock.Any(), userID).Return(nil, errors.New("repository error"))
+			},
+			userID:      userID,
+			expected:    nil,
+			expectedErr: errors.New("repository error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			result, err := service.GetCoinHistory(context.Background(), tt.userID)
 			if tt.expectedErr != nil {
 				assert.EqualError(t, err, tt.expectedErr.Error())
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
 			}
 		})
 	}
@@ -2,23 +2,38 @@ package services
 
 import (
 	"context"
-	"errors"
+	"strings"
+	"time"
 
 	"github.com/derticom/merch-store/internal/models"
+	"github.com/derticom/merch-store/internal/tracing"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
-const initialBalance = 1000
+// RegisterUser создает пользователя с начальным балансом и начисляет подходящие приветственные бонусы.
+// Непустой inviteCode должен совпадать с кодом действующего правила бонуса.
+func (s *Service) RegisterUser(ctx context.Context, username, password, inviteCode string) (_ *models.User, err error) {
+	ctx, span := tracing.Start(ctx, "Service.RegisterUser")
+	defer tracing.End(span, &err)
+
+	if username == "" || password == "" {
+		return nil, ErrEmptyCredentials
+	}
+
+	inviteCode = strings.TrimSpace(inviteCode)
+	bonuses, err := s.welcomeBonusesFor(time.Now(), inviteCode)
+	if err != nil {
+		return nil, err
+	}
 
-func (s *Service) RegisterUser(ctx context.Context, username, password string) (*models.User, error) {
 	existingUser, err := s.repo.GetUserByUsername(ctx, username)
 	if err != nil {
 		return nil, err
 	}
 	if existingUser != nil {
-		return nil, errors.New("username already exists")
+		return nil, ErrUsernameTaken
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -30,36 +45,110 @@ func (s *Service) RegisterUser(ctx context.Context, username, password string) (
 		ID:       uuid.New(),
 		Username: username,
 		Password: string(hashedPassword),
-		Coins:    initialBalance,
+		Coins:    s.initialBalance,
+		Role:     models.RoleEmployee,
 	}
 
-	if err := s.repo.CreateUser(ctx, user); err != nil {
+	err = s.repo.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateUser(ctx, user); err != nil {
+			return err
+		}
+
+		for _, bonus := range bonuses {
+			if err := s.repo.GrantBonus(ctx, user.ID, bonus.Amount, bonus.Reason()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	for _, bonus := range bonuses {
+		user.Coins += bonus.Amount
+	}
+
 	return user, nil
 }
 
-func (s *Service) AuthenticateUser(ctx context.Context, username, password string) (*models.User, error) {
+// welcomeBonusesFor выбирает бонусы для регистрации в момент at. Код приглашения, не подходящий
+// ни к одному действующему правилу, считается ошибкой, чтобы пользователь узнал об опечатке.
+func (s *Service) welcomeBonusesFor(at time.Time, inviteCode string) ([]models.WelcomeBonus, error) {
+	var bonuses []models.WelcomeBonus
+	codeUsed := false
+	for _, bonus := range s.welcomeBonuses {
+		if !bonus.Applies(at, inviteCode) {
+			continue
+		}
+		bonuses = append(bonuses, bonus)
+		if bonus.InviteCode != "" {
+			codeUsed = true
+		}
+	}
+
+	if inviteCode != "" && !codeUsed {
+		return nil, ErrInvalidInviteCode
+	}
+
+	return bonuses, nil
+}
+
+func (s *Service) AuthenticateUser(ctx context.Context, username, password string) (_ *models.User, err error) {
+	ctx, span := tracing.Start(ctx, "Service.AuthenticateUser")
+	defer tracing.End(span, &err)
+
 	user, err := s.repo.GetUserByUsername(ctx, username)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, errors.New("invalid password")
+		return nil, ErrInvalidPassword
 	}
 
 	return user, nil
 }
 
-func (s *Service) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+func (s *Service) GetUserByID(ctx context.Context, id uuid.UUID) (_ *models.User, err error) {
+	ctx, span := tracing.Start(ctx, "Service.GetUserByID")
+	defer tracing.End(span, &err)
+
 	return s.repo.GetUserByID(ctx, id)
 }
 
-func (s *Service) UpdateUserCoins(ctx context.Context, id uuid.UUID, coins int) error {
+func (s *Service) UpdateUserCoins(ctx context.Context, id uuid.UUID, coins int) (err error) {
+	ctx, span := tracing.Start(ctx, "Service.UpdateUserCoins")
+	defer tracing.End(span, &err)
+
 	return s.repo.UpdateUserCoins(ctx, id, coins)
 }
+
+// SetUserRole назначает пользователю роль. Новая роль попадает в токен при следующем входе или обновлении токена.
+func (s *Service) SetUserRole(ctx context.Context, username, role string) (_ *models.User, err error) {
+	ctx, span := tracing.Start(ctx, "Service.SetUserRole")
+	defer tracing.End(span, &err)
+
+	if !models.IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := s.repo.UpdateUserRole(ctx, user.ID, role); err != nil {
+		return nil, err
+	}
+	user.Role = role
+
+	return user, nil
+}
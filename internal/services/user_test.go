@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/derticom/merch-store/internal/models"
 	"github.com/derticom/merch-store/internal/services/mocks"
@@ -19,40 +20,88 @@ func TestService_RegisterUser(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mock_services.NewMockRepository(ctrl)
-	service := New(mockRepo)
+	service := New(mockRepo,
+		WithInitialBalance(500),
+		WithWelcomeBonuses(
+			models.WelcomeBonus{Name: "launch", Amount: 200, From: time.Now().Add(-time.Hour), Until: time.Now().Add(time.Hour)},
+			models.WelcomeBonus{Name: "last year", Amount: 300, Until: time.Now().Add(-24 * time.Hour)},
+			models.WelcomeBonus{Name: "referral", Amount: 100, InviteCode: "FRIENDS"},
+		),
+	)
 
 	username := "testuser"
 	password := "testpassword"
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 
+	withinTx := func() {
+		mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+	createUser := func() {
+		mockRepo.EXPECT().GetUserByUsername(gomock.Any(), username).Return(nil, nil)
+		mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, user *models.User) error {
+				assert.Equal(t, 500, user.Coins)
+				user.Password = string(hashedPassword)
+				return nil
+			})
+	}
+
 	tests := []struct {
 		name        string
 		setup       func()
 		username    string
 		password    string
+		inviteCode  string
 		expected    *models.User
 		expectedErr error
 	}{
 		{
-			name: "successful registration",
+			name: "successful registration with bonus in signup window",
 			setup: func() {
-				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), username).Return(nil, nil)
-				mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).DoAndReturn(
-					func(_ context.Context, user *models.User) error {
-						user.ID = uuid.New()
-						user.Password = string(hashedPassword)
-						return nil
-					})
+				withinTx()
+				createUser()
+				mockRepo.EXPECT().GrantBonus(gomock.Any(), gomock.Any(), 200, "welcome bonus: launch").Return(nil)
 			},
 			username: username,
 			password: password,
 			expected: &models.User{
 				Username: username,
 				Password: string(hashedPassword),
-				Coins:    initialBalance,
+				Coins:    700,
+				Role:     models.RoleEmployee,
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "registration with invite code",
+			setup: func() {
+				withinTx()
+				createUser()
+				mockRepo.EXPECT().GrantBonus(gomock.Any(), gomock.Any(), 200, "welcome bonus: launch").Return(nil)
+				mockRepo.EXPECT().GrantBonus(gomock.Any(), gomock.Any(), 100, "welcome bonus: referral").Return(nil)
+			},
+			username:   username,
+			password:   password,
+			inviteCode: " FRIENDS ",
+			expected: &models.User{
+				Username: username,
+				Coins:    800,
+				Role:     models.RoleEmployee,
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "unknown invite code",
+			setup:       func() {},
+			username:    username,
+			password:    password,
+			inviteCode:  "ENEMIES",
+			expected:    nil,
+			expectedErr: ErrInvalidInviteCode,
+		},
 		{
 			name: "username already exists",
 			setup: func() {
@@ -61,11 +110,20 @@ func TestService_RegisterUser(t *testing.T) {
 			username:    username,
 			password:    password,
 			expected:    nil,
-			expectedErr: errors.New("username already exists"),
+			expectedErr: ErrUsernameTaken,
+		},
+		{
+			name:        "empty credentials",
+			setup:       func() {},
+			username:    username,
+			password:    "",
+			expected:    nil,
+			expectedErr: ErrEmptyCredentials,
 		},
 		{
 			name: "error creating user",
 			setup: func() {
+				withinTx()
 				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), username).Return(nil, nil)
 				mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(errors.New("create error"))
 			},
@@ -74,24 +132,58 @@ func TestService_RegisterUser(t *testing.T) {
 			expected:    nil,
 			expectedErr: errors.New("create error"),
 		},
+		{
+			name: "error granting bonus",
+			setup: func() {
+				withinTx()
+				createUser()
+				mockRepo.EXPECT().GrantBonus(gomock.Any(), gomock.Any(), 200, "welcome bonus: launch").
+					Return(errors.New("grant error"))
+			},
+			username:    username,
+			password:    password,
+			expected:    nil,
+			expectedErr: errors.New("grant error"),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			user, err := service.RegisterUser(context.Background(), tt.username, tt.password)
+			user, err := service.RegisterUser(context.Background(), tt.username, tt.password, tt.inviteCode)
 			if tt.expectedErr != nil {
 				assert.EqualError(t, err, tt.expectedErr.Error())
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expected.Username, user.Username)
 				assert.Equal(t, tt.expected.Coins, user.Coins)
+				assert.Equal(t, tt.expected.Role, user.Role)
 				assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)))
 			}
 		})
 	}
 }
 
+func TestService_RegisterUser_DefaultBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "newbie").Return(nil, nil)
+	mockRepo.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(nil)
+
+	user, err := service.RegisterUser(context.Background(), "newbie", "password", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, defaultInitialBalance, user.Coins)
+}
+
 func TestService_AuthenticateUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -133,7 +225,7 @@ func TestService_AuthenticateUser(t *testing.T) {
 			username:    username,
 			password:    password,
 			expected:    nil,
-			expectedErr: errors.New("user not found"),
+			expectedErr: ErrUserNotFound,
 		},
 		{
 			name: "invalid password",
@@ -143,7 +235,7 @@ func TestService_AuthenticateUser(t *testing.T) {
 			username:    username,
 			password:    "wrongpassword",
 			expected:    nil,
-			expectedErr: errors.New("invalid password"),
+			expectedErr: ErrInvalidPassword,
 		},
 	}
 
@@ -263,3 +355,57 @@ func TestService_UpdateUserCoins(t *testing.T) {
 		})
 	}
 }
+
+func TestService_SetUserRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_services.NewMockRepository(ctrl)
+	service := New(mockRepo)
+
+	user := &models.User{ID: uuid.New(), Username: "testuser", Role: models.RoleEmployee}
+
+	tests := []struct {
+		name        string
+		setup       func()
+		role        string
+		expectedErr error
+	}{
+		{
+			name: "role is updated",
+			setup: func() {
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), user.Username).Return(user, nil)
+				mockRepo.EXPECT().UpdateUserRole(gomock.Any(), user.ID, models.RoleAdmin).Return(nil)
+			},
+			role:        models.RoleAdmin,
+			expectedErr: nil,
+		},
+		{
+			name:        "unknown role",
+			setup:       func() {},
+			role:        "superuser",
+			expectedErr: ErrInvalidRole,
+		},
+		{
+			name: "user not found",
+			setup: func() {
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), user.Username).Return(nil, nil)
+			},
+			role:        models.RoleAuditor,
+			expectedErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			updated, err := service.SetUserRole(context.Background(), user.Username, tt.role)
+
+			assert.Equal(t, tt.expectedErr, err)
+			if tt.expectedErr == nil {
+				assert.Equal(t, tt.role, updated.Role)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package tokens
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+)
+
+// JWK - публичный ключ в формате RFC 7517.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519.
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS - набор публичных ключей, по которым другие сервисы проверяют наши токены.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS возвращает все ключи проверки, включая выводимые из ротации. Общий секрет HS256 не публикуется.
+func (m *Manager) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, k := range m.keys {
+		if !k.publishKey {
+			continue
+		}
+
+		jwk := JWK{KeyID: k.id, Use: "sig", Algorithm: k.method.Alg()}
+		switch pub := k.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+
+	return set
+}
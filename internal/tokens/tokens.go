@@ -0,0 +1,192 @@
+package tokens
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/derticom/merch-store/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Поддерживаемые алгоритмы подписи.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// defaultAccessTokenTTL используется, если время жизни access-токена не задано в конфигурации.
+const defaultAccessTokenTTL = 15 * time.Minute
+
+var (
+	ErrUnknownKey        = errors.New("unknown signing key")
+	ErrNoSigningKey      = errors.New("signing key is not configured")
+	ErrSecretNotSet      = errors.New("JWT secret key is not set")
+	errAlgorithmMismatch = errors.New("key algorithm does not match token algorithm")
+	errKeyPairMismatch   = errors.New("public key does not match private key")
+)
+
+type key struct {
+	id         string
+	method     jwt.SigningMethod
+	signKey    crypto.PrivateKey // nil, если ключ только проверяет подпись.
+	verifyKey  crypto.PublicKey
+	publishKey bool // публиковать ли ключ в JWKS; секрет HS256 публиковать нельзя.
+}
+
+// Manager подписывает и проверяет access-токены. Ключи загружаются один раз при старте.
+type Manager struct {
+	keys    map[string]*key
+	signing *key
+	methods []string
+	ttl     time.Duration
+}
+
+// New загружает ключи из конфигурации. Если ключи не заданы, токены подписываются HS256 с общим секретом.
+func New(cfg config.Auth) (*Manager, error) {
+	m := &Manager{
+		keys: make(map[string]*key),
+		ttl:  cfg.AccessTokenTTL,
+	}
+	if m.ttl <= 0 {
+		m.ttl = defaultAccessTokenTTL
+	}
+
+	if len(cfg.Keys) == 0 {
+		if cfg.Secret == "" {
+			return nil, ErrSecretNotSet
+		}
+		m.signing = &key{method: jwt.SigningMethodHS256, signKey: []byte(cfg.Secret), verifyKey: []byte(cfg.Secret)}
+		m.keys[""] = m.signing
+		m.methods = []string{AlgHS256}
+		return m, nil
+	}
+
+	methods := make(map[string]struct{})
+	for _, kc := range cfg.Keys {
+		if _, ok := m.keys[kc.ID]; ok || kc.ID == "" {
+			return nil, fmt.Errorf("key id %q is empty or duplicated", kc.ID)
+		}
+
+		k, err := loadKey(kc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key %q: %w", kc.ID, err)
+		}
+		m.keys[k.id] = k
+
+		if _, ok := methods[kc.Algorithm]; !ok {
+			methods[kc.Algorithm] = struct{}{}
+			m.methods = append(m.methods, kc.Algorithm)
+		}
+	}
+
+	signing, ok := m.keys[cfg.SigningKeyID]
+	if !ok || signing.signKey == nil {
+		return nil, fmt.Errorf("%w: %q", ErrNoSigningKey, cfg.SigningKeyID)
+	}
+	m.signing = signing
+
+	return m, nil
+}
+
+// TTL возвращает время жизни выдаваемых access-токенов.
+func (m *Manager) TTL() time.Duration {
+	return m.ttl
+}
+
+// Sign подписывает claims текущим ключом и указывает его kid в заголовке токена.
+func (m *Manager) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(m.signing.method, claims)
+	if m.signing.id != "" {
+		token.Header["kid"] = m.signing.id
+	}
+
+	return token.SignedString(m.signing.signKey)
+}
+
+// Parse проверяет подпись и срок действия токена. Ключ выбирается по kid, а алгоритм токена
+// должен совпадать с алгоритмом ключа, чтобы нельзя было подменить RS256 на HS256 с публичным ключом.
+func (m *Manager) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, m.keyFunc, jwt.WithValidMethods(m.methods))
+}
+
+func (m *Manager) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	k, ok := m.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, errAlgorithmMismatch
+	}
+
+	return k.verifyKey, nil
+}
+
+func loadKey(cfg config.JWTKey) (*key, error) {
+	k := &key{id: cfg.ID, publishKey: true}
+
+	var (
+		parsePrivate func([]byte) (crypto.PrivateKey, error)
+		parsePublic  func([]byte) (crypto.PublicKey, error)
+	)
+	switch cfg.Algorithm {
+	case AlgRS256:
+		k.method = jwt.SigningMethodRS256
+		parsePrivate = func(b []byte) (crypto.PrivateKey, error) { return jwt.ParseRSAPrivateKeyFromPEM(b) }
+		parsePublic = func(b []byte) (crypto.PublicKey, error) { return jwt.ParseRSAPublicKeyFromPEM(b) }
+	case AlgEdDSA:
+		k.method = jwt.SigningMethodEdDSA
+		parsePrivate = jwt.ParseEdPrivateKeyFromPEM
+		parsePublic = jwt.ParseEdPublicKeyFromPEM
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", cfg.Algorithm)
+	}
+
+	if cfg.PrivateKeyFile != "" {
+		data, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if k.signKey, err = parsePrivate(data); err != nil {
+			return nil, err
+		}
+		k.verifyKey = k.signKey.(crypto.Signer).Public()
+	}
+
+	if cfg.PublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		public, err := parsePublic(data)
+		if err != nil {
+			return nil, err
+		}
+		// Иначе токены подписывались бы одним ключом, а в JWKS публиковался другой.
+		if k.verifyKey != nil && !k.verifyKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(public) {
+			return nil, errKeyPairMismatch
+		}
+		k.verifyKey = public
+	}
+
+	if k.verifyKey == nil {
+		return nil, errors.New("neither private nor public key file is set")
+	}
+
+	// jwt ожидает ключи конкретных типов, а не интерфейсы.
+	switch pub := k.verifyKey.(type) {
+	case *rsa.PublicKey, ed25519.PublicKey:
+	default:
+		return nil, fmt.Errorf("unexpected public key type %T", pub)
+	}
+
+	return k, nil
+}
@@ -0,0 +1,240 @@
+package tokens
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/derticom/merch-store/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+
+	return path
+}
+
+func rsaKeyFiles(t *testing.T) (privatePath, publicPath string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	return writePEM(t, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)),
+		writePEM(t, "rsa.pub", "PUBLIC KEY", pub)
+}
+
+func ed25519KeyFiles(t *testing.T) (privatePath, publicPath string) {
+	t.Helper()
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	privDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	return writePEM(t, "ed.pem", "PRIVATE KEY", privDER), writePEM(t, "ed.pub", "PUBLIC KEY", pubDER)
+}
+
+func claims() jwt.MapClaims {
+	return jwt.MapClaims{"sub": "user", "exp": time.Now().Add(time.Minute).Unix()}
+}
+
+func TestManager_SignAndParse(t *testing.T) {
+	rsaPrivate, rsaPublic := rsaKeyFiles(t)
+	edPrivate, _ := ed25519KeyFiles(t)
+
+	tests := []struct {
+		name string
+		cfg  config.Auth
+		alg  string
+	}{
+		{
+			name: "HS256 secret",
+			cfg:  config.Auth{Secret: "secret"},
+			alg:  AlgHS256,
+		},
+		{
+			name: "RS256",
+			cfg: config.Auth{
+				SigningKeyID: "rsa-1",
+				Keys:         []config.JWTKey{{ID: "rsa-1", Algorithm: AlgRS256, PrivateKeyFile: rsaPrivate}},
+			},
+			alg: AlgRS256,
+		},
+		{
+			name: "RS256 with matching public key file",
+			cfg: config.Auth{
+				SigningKeyID: "rsa-1",
+				Keys: []config.JWTKey{{
+					ID: "rsa-1", Algorithm: AlgRS256, PrivateKeyFile: rsaPrivate, PublicKeyFile: rsaPublic,
+				}},
+			},
+			alg: AlgRS256,
+		},
+		{
+			name: "EdDSA",
+			cfg: config.Auth{
+				SigningKeyID: "ed-1",
+				Keys:         []config.JWTKey{{ID: "ed-1", Algorithm: AlgEdDSA, PrivateKeyFile: edPrivate}},
+			},
+			alg: AlgEdDSA,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, err := New(tt.cfg)
+			require.NoError(t, err)
+
+			signed, err := manager.Sign(claims())
+			require.NoError(t, err)
+
+			token, err := manager.Parse(signed, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.True(t, token.Valid)
+			assert.Equal(t, tt.alg, token.Method.Alg())
+			if tt.cfg.SigningKeyID != "" {
+				assert.Equal(t, tt.cfg.SigningKeyID, token.Header["kid"])
+			}
+		})
+	}
+}
+
+func TestManager_Rotation(t *testing.T) {
+	oldPrivate, oldPublic := rsaKeyFiles(t)
+	newPrivate, _ := ed25519KeyFiles(t)
+
+	before, err := New(config.Auth{
+		SigningKeyID: "old",
+		Keys:         []config.JWTKey{{ID: "old", Algorithm: AlgRS256, PrivateKeyFile: oldPrivate}},
+	})
+	require.NoError(t, err)
+	oldToken, err := before.Sign(claims())
+	require.NoError(t, err)
+
+	// Старый ключ оставлен только для проверки, подписывает новый.
+	after, err := New(config.Auth{
+		SigningKeyID: "new",
+		Keys: []config.JWTKey{
+			{ID: "old", Algorithm: AlgRS256, PublicKeyFile: oldPublic},
+			{ID: "new", Algorithm: AlgEdDSA, PrivateKeyFile: newPrivate},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = after.Parse(oldToken, jwt.MapClaims{})
+	assert.NoError(t, err, "tokens signed with a retired key must stay valid")
+
+	newToken, err := after.Sign(claims())
+	require.NoError(t, err)
+	token, err := after.Parse(newToken, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "new", token.Header["kid"])
+
+	jwks := after.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "new", jwks.Keys[0].KeyID)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+	assert.NotEmpty(t, jwks.Keys[0].X)
+	assert.Equal(t, "old", jwks.Keys[1].KeyID)
+	assert.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+}
+
+func TestManager_RejectsForeignTokens(t *testing.T) {
+	rsaPrivate, _ := rsaKeyFiles(t)
+
+	manager, err := New(config.Auth{
+		SigningKeyID: "rsa-1",
+		Keys:         []config.JWTKey{{ID: "rsa-1", Algorithm: AlgRS256, PrivateKeyFile: rsaPrivate}},
+	})
+	require.NoError(t, err)
+
+	hmac, err := New(config.Auth{Secret: "secret"})
+	require.NoError(t, err)
+	hsToken, err := hmac.Sign(claims())
+	require.NoError(t, err)
+
+	unknownKid := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+	unknownKid.Header["kid"] = "other"
+	unknownToken, err := unknownKid.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	for name, token := range map[string]string{"HS256 token": hsToken, "unknown kid": unknownToken} {
+		t.Run(name, func(t *testing.T) {
+			_, err := manager.Parse(token, jwt.MapClaims{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	rsaPrivate, rsaPublic := rsaKeyFiles(t)
+	_, otherRSAPublic := rsaKeyFiles(t)
+	edPrivate, _ := ed25519KeyFiles(t)
+	_, otherEdPublic := ed25519KeyFiles(t)
+
+	tests := []struct {
+		name string
+		cfg  config.Auth
+	}{
+		{name: "no secret and no keys", cfg: config.Auth{}},
+		{
+			name: "signing key without private part",
+			cfg: config.Auth{
+				SigningKeyID: "rsa-1",
+				Keys:         []config.JWTKey{{ID: "rsa-1", Algorithm: AlgRS256, PublicKeyFile: rsaPublic}},
+			},
+		},
+		{
+			name: "RS256 public key from another pair",
+			cfg: config.Auth{
+				SigningKeyID: "rsa-1",
+				Keys: []config.JWTKey{{
+					ID: "rsa-1", Algorithm: AlgRS256, PrivateKeyFile: rsaPrivate, PublicKeyFile: otherRSAPublic,
+				}},
+			},
+		},
+		{
+			name: "EdDSA public key from another pair",
+			cfg: config.Auth{
+				SigningKeyID: "ed-1",
+				Keys: []config.JWTKey{{
+					ID: "ed-1", Algorithm: AlgEdDSA, PrivateKeyFile: edPrivate, PublicKeyFile: otherEdPublic,
+				}},
+			},
+		},
+		{
+			name: "unsupported algorithm",
+			cfg: config.Auth{
+				SigningKeyID: "k",
+				Keys:         []config.JWTKey{{ID: "k", Algorithm: "ES256", PublicKeyFile: rsaPublic}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
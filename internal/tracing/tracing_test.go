@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/derticom/merch-store/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetup_StdoutFileExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.json")
+
+	shutdown, err := Setup(context.Background(), config.Tracing{
+		Exporter:    ExporterStdout,
+		File:        file,
+		SampleRatio: 1,
+	})
+	require.NoError(t, err)
+
+	func() (err error) {
+		_, span := Start(context.Background(), "Service.BuyItem")
+		defer End(span, &err)
+		return errors.New("insufficient coins")
+	}()
+
+	require.NoError(t, shutdown(context.Background()))
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"Service.BuyItem"`)
+	assert.Contains(t, string(data), "insufficient coins")
+	assert.Contains(t, string(data), ServiceName)
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), config.Tracing{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
+
+func TestSetup_PropagatesW3CTraceContext(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.Tracing{Exporter: ExporterNone})
+	require.NoError(t, err)
+	defer shutdown(context.Background())
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	header := http.Header{}
+	header.Set("traceparent", traceparent)
+
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	assert.True(t, spanCtx.IsRemote())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanCtx.TraceID().String())
+}
@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'employee'
+    CHECK (role IN ('employee', 'admin', 'auditor'));
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS role;
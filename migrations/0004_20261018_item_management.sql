@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE items ADD COLUMN IF NOT EXISTS retired_at TIMESTAMP WITH TIME ZONE;
+
+-- Переименование товара не должно ломать историю покупок.
+ALTER TABLE purchase DROP CONSTRAINT IF EXISTS purchase_item_fkey;
+ALTER TABLE purchase ADD CONSTRAINT purchase_item_fkey
+    FOREIGN KEY (item) REFERENCES items(name) ON UPDATE CASCADE;
+
+-- +goose Down
+ALTER TABLE purchase DROP CONSTRAINT IF EXISTS purchase_item_fkey;
+ALTER TABLE purchase ADD CONSTRAINT purchase_item_fkey FOREIGN KEY (item) REFERENCES items(name);
+
+ALTER TABLE items DROP COLUMN IF EXISTS retired_at;
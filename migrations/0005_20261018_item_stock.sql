@@ -0,0 +1,23 @@
+-- +goose Up
+-- NULL означает неограниченное количество.
+ALTER TABLE items ADD COLUMN IF NOT EXISTS stock INT CHECK (stock >= 0);
+ALTER TABLE items ADD COLUMN IF NOT EXISTS per_user_limit INT CHECK (per_user_limit > 0);
+
+CREATE TABLE IF NOT EXISTS stock_movements
+(
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    item        TEXT NOT NULL REFERENCES items(name) ON UPDATE CASCADE,
+    delta       INT NOT NULL,
+    kind        TEXT NOT NULL CHECK (kind IN ('purchase', 'restock')),
+    user_id     UUID REFERENCES users(id),
+    purchase_id UUID REFERENCES purchase(id),
+    note        TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS stock_movements_item_idx ON stock_movements (item, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS stock_movements;
+ALTER TABLE items DROP COLUMN IF EXISTS per_user_limit;
+ALTER TABLE items DROP COLUMN IF EXISTS stock;
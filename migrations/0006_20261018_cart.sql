@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS cart_items
+(
+    user_id  UUID NOT NULL REFERENCES users(id),
+    item     TEXT NOT NULL REFERENCES items(name) ON UPDATE CASCADE,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    added_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, item)
+);
+
+-- Цена на момент покупки: цена товара может меняться, а сумма заказа - нет.
+ALTER TABLE purchase ADD COLUMN IF NOT EXISTS price INT;
+UPDATE purchase p SET price = i.price FROM items i WHERE p.item = i.name AND p.price IS NULL;
+ALTER TABLE purchase ALTER COLUMN price SET NOT NULL;
+
+-- +goose Down
+ALTER TABLE purchase DROP COLUMN IF EXISTS price;
+DROP TABLE IF EXISTS cart_items;
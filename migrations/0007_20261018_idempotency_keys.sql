@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS idempotency_keys
+(
+    user_id      UUID NOT NULL REFERENCES users(id),
+    key          TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    -- status_code IS NULL, пока исходный запрос выполняется.
+    status_code  INT,
+    content_type TEXT NOT NULL DEFAULT '',
+    response     BYTEA,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE purchase ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'completed'
+    CHECK (status IN ('completed', 'refund_requested', 'refunded'));
+ALTER TABLE purchase ADD COLUMN IF NOT EXISTS refund_requested_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE purchase ADD COLUMN IF NOT EXISTS refunded_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS purchase_refund_requested_idx ON purchase (refund_requested_at)
+    WHERE status = 'refund_requested';
+
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_kind_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_kind_check
+    CHECK (kind IN ('purchase', 'restock', 'refund'));
+
+-- +goose Down
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_kind_check;
+DELETE FROM stock_movements WHERE kind = 'refund';
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_kind_check
+    CHECK (kind IN ('purchase', 'restock'));
+
+DROP INDEX IF EXISTS purchase_refund_requested_idx;
+ALTER TABLE purchase DROP COLUMN IF EXISTS refunded_at;
+ALTER TABLE purchase DROP COLUMN IF EXISTS refund_requested_at;
+ALTER TABLE purchase DROP COLUMN IF EXISTS status;
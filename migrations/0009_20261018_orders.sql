@@ -0,0 +1,53 @@
+-- +goose Up
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check
+    CHECK (role IN ('employee', 'admin', 'auditor', 'warehouse'));
+
+CREATE TABLE IF NOT EXISTS orders
+(
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID NOT NULL REFERENCES users(id),
+    status     TEXT NOT NULL DEFAULT 'placed'
+        CHECK (status IN ('placed', 'packed', 'ready_for_pickup', 'delivered', 'cancelled')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS orders_status_idx ON orders (status, created_at);
+
+-- Журнал переходов: у каждого статуса заказа есть время и автор.
+CREATE TABLE IF NOT EXISTS order_transitions
+(
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id    UUID NOT NULL REFERENCES orders(id),
+    from_status TEXT,
+    to_status   TEXT NOT NULL,
+    actor_id    UUID REFERENCES users(id),
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS order_transitions_order_idx ON order_transitions (order_id, created_at);
+
+ALTER TABLE purchase ADD COLUMN IF NOT EXISTS order_id UUID REFERENCES orders(id);
+
+-- Покупки, сделанные до появления заказов, выдавались вне сервиса: каждая становится отдельным
+-- выданным заказом с тем же идентификатором.
+INSERT INTO orders (id, user_id, status, created_at, updated_at)
+SELECT id, user_id, 'delivered', created_at, created_at FROM purchase WHERE order_id IS NULL;
+INSERT INTO order_transitions (order_id, from_status, to_status, created_at)
+SELECT id, NULL, 'delivered', created_at FROM purchase WHERE order_id IS NULL;
+UPDATE purchase SET order_id = id WHERE order_id IS NULL;
+
+ALTER TABLE purchase ALTER COLUMN order_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS purchase_order_idx ON purchase (order_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS purchase_order_idx;
+ALTER TABLE purchase DROP COLUMN IF EXISTS order_id;
+DROP TABLE IF EXISTS order_transitions;
+DROP TABLE IF EXISTS orders;
+
+UPDATE users SET role = 'employee' WHERE role = 'warehouse';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check
+    CHECK (role IN ('employee', 'admin', 'auditor'));
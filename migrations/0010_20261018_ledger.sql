@@ -0,0 +1,121 @@
+-- +goose Up
+-- Проводка - одна хозяйственная операция (начисление, перевод, покупка, возврат, корректировка).
+CREATE TABLE IF NOT EXISTS ledger_postings
+(
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind         TEXT NOT NULL CHECK (kind IN ('grant', 'transfer', 'purchase', 'refund', 'adjustment')),
+    -- Перевод, заказ или покупка, к которой относится проводка.
+    reference_id UUID,
+    note         TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Записи проводки: отрицательная сумма списывает со счета, положительная зачисляет.
+-- Счета пользователей задаются user_id, системные счета: issuance - выпуск монет, store - выручка магазина.
+CREATE TABLE IF NOT EXISTS ledger_entries
+(
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    posting_id UUID NOT NULL REFERENCES ledger_postings(id),
+    account    TEXT NOT NULL CHECK (account IN ('user', 'issuance', 'store')),
+    user_id    UUID REFERENCES users(id),
+    amount     INT NOT NULL CHECK (amount <> 0),
+    CHECK ((account = 'user') = (user_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS ledger_entries_posting_idx ON ledger_entries (posting_id);
+CREATE INDEX IF NOT EXISTS ledger_entries_user_idx ON ledger_entries (user_id) WHERE user_id IS NOT NULL;
+
+-- Сумма записей каждой проводки должна быть нулевой. Проверка отложена до фиксации транзакции,
+-- так как записи проводки вставляются по одной.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION ledger_check_balanced() RETURNS trigger AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger_entries WHERE posting_id = NEW.posting_id) <> 0 THEN
+        RAISE EXCEPTION 'ledger posting % is not balanced', NEW.posting_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE CONSTRAINT TRIGGER ledger_entries_balanced AFTER INSERT ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED FOR EACH ROW EXECUTE FUNCTION ledger_check_balanced();
+
+-- Журнал только дополняется: ошибки исправляются новыми проводками.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION ledger_forbid_changes() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER ledger_postings_append_only BEFORE UPDATE OR DELETE ON ledger_postings
+    FOR EACH ROW EXECUTE FUNCTION ledger_forbid_changes();
+CREATE TRIGGER ledger_entries_append_only BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_forbid_changes();
+
+-- Перенос истории. Начальный баланс каждого пользователя - 1000 монет.
+WITH p AS (
+    INSERT INTO ledger_postings (kind, reference_id, note)
+    SELECT 'grant', id, 'initial balance' FROM users
+    RETURNING id, reference_id
+)
+INSERT INTO ledger_entries (posting_id, account, user_id, amount)
+SELECT p.id, 'user', p.reference_id, 1000 FROM p
+UNION ALL
+SELECT p.id, 'issuance', NULL, -1000 FROM p;
+
+WITH p AS (
+    INSERT INTO ledger_postings (kind, reference_id, created_at)
+    SELECT 'transfer', id, created_at FROM transactions WHERE amount <> 0
+    RETURNING id, reference_id
+)
+INSERT INTO ledger_entries (posting_id, account, user_id, amount)
+SELECT p.id, 'user', t.from_user, -t.amount FROM p JOIN transactions t ON t.id = p.reference_id
+UNION ALL
+SELECT p.id, 'user', t.to_user, t.amount FROM p JOIN transactions t ON t.id = p.reference_id;
+
+-- До появления проводок по заказам каждая покупка была отдельным заказом с тем же идентификатором.
+WITH p AS (
+    INSERT INTO ledger_postings (kind, reference_id, created_at)
+    SELECT 'purchase', id, created_at FROM purchase WHERE price <> 0
+    RETURNING id, reference_id
+)
+INSERT INTO ledger_entries (posting_id, account, user_id, amount)
+SELECT p.id, 'user', pu.user_id, -pu.price FROM p JOIN purchase pu ON pu.id = p.reference_id
+UNION ALL
+SELECT p.id, 'store', NULL, pu.price FROM p JOIN purchase pu ON pu.id = p.reference_id;
+
+WITH p AS (
+    INSERT INTO ledger_postings (kind, reference_id, created_at)
+    SELECT 'refund', id, refunded_at FROM purchase WHERE status = 'refunded' AND price <> 0
+    RETURNING id, reference_id
+)
+INSERT INTO ledger_entries (posting_id, account, user_id, amount)
+SELECT p.id, 'user', pu.user_id, pu.price FROM p JOIN purchase pu ON pu.id = p.reference_id
+UNION ALL
+SELECT p.id, 'store', NULL, -pu.price FROM p JOIN purchase pu ON pu.id = p.reference_id;
+
+-- Расхождения из-за прямых изменений users.coins фиксируются корректировкой, чтобы журнал сошелся с балансами.
+WITH diff AS (
+    SELECT u.id AS user_id, u.coins - COALESCE(SUM(e.amount), 0) AS amount
+    FROM users u
+    LEFT JOIN ledger_entries e ON e.user_id = u.id
+    GROUP BY u.id
+    HAVING u.coins <> COALESCE(SUM(e.amount), 0)
+), p AS (
+    INSERT INTO ledger_postings (kind, reference_id, note)
+    SELECT 'adjustment', user_id, 'opening balance adjustment' FROM diff
+    RETURNING id, reference_id
+)
+INSERT INTO ledger_entries (posting_id, account, user_id, amount)
+SELECT p.id, 'user', d.user_id, d.amount FROM p JOIN diff d ON d.user_id = p.reference_id
+UNION ALL
+SELECT p.id, 'issuance', NULL, -d.amount FROM p JOIN diff d ON d.user_id = p.reference_id;
+
+-- +goose Down
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_postings;
+DROP FUNCTION IF EXISTS ledger_forbid_changes();
+DROP FUNCTION IF EXISTS ledger_check_balanced();
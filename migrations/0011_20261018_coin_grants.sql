@@ -0,0 +1,18 @@
+-- +goose Up
+-- Начисления и списания монет администраторами записываются проводками grant и deduction
+-- с причиной в note и автором в created_by.
+ALTER TABLE ledger_postings DROP CONSTRAINT IF EXISTS ledger_postings_kind_check;
+ALTER TABLE ledger_postings ADD CONSTRAINT ledger_postings_kind_check
+    CHECK (kind IN ('grant', 'deduction', 'transfer', 'purchase', 'refund', 'adjustment'));
+ALTER TABLE ledger_postings ADD COLUMN IF NOT EXISTS created_by UUID REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS ledger_postings_reference_idx ON ledger_postings (reference_id);
+
+-- +goose Down
+-- Журнал только дополняется, поэтому уже записанные проводки deduction остаются. Старое ограничение
+-- добавляется без проверки существующих строк и запрещает только новые списания.
+DROP INDEX IF EXISTS ledger_postings_reference_idx;
+ALTER TABLE ledger_postings DROP COLUMN IF EXISTS created_by;
+ALTER TABLE ledger_postings DROP CONSTRAINT IF EXISTS ledger_postings_kind_check;
+ALTER TABLE ledger_postings ADD CONSTRAINT ledger_postings_kind_check
+    CHECK (kind IN ('grant', 'transfer', 'purchase', 'refund', 'adjustment')) NOT VALID;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Начальный баланс задается в конфигурации и зачисляется проводкой журнала, а не значением по умолчанию.
+ALTER TABLE users ALTER COLUMN coins SET DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE users ALTER COLUMN coins SET DEFAULT 1000;
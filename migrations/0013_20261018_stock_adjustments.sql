@@ -0,0 +1,11 @@
+-- +goose Up
+-- Администратор может задать остаток напрямую; изменение записывается корректировкой.
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_kind_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_kind_check
+    CHECK (kind IN ('purchase', 'restock', 'refund', 'adjustment'));
+
+-- +goose Down
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_kind_check;
+DELETE FROM stock_movements WHERE kind = 'adjustment';
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_kind_check
+    CHECK (kind IN ('purchase', 'restock', 'refund'));
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -33,6 +36,50 @@ func TestPurchaseItem(t *testing.T) {
 	assert.Equal(t, 950, coins, "User should have 950 coins after buying a book")
 }
 
+func TestConcurrentPurchases(t *testing.T) {
+	// Перед тестом выполнить запуск контейнеров
+	// docker compose up --build
+
+	const (
+		requests     = 300
+		item         = "socks"
+		itemPrice    = 10
+		startBalance = 1000
+	)
+
+	username := "concurrent-" + uuid.NewString()
+	_, token := registerUser(t, username, "testpassword")
+	assert.NotEmpty(t, token, "Token should not be empty")
+
+	var (
+		wg        sync.WaitGroup
+		succeeded atomic.Int64
+		rejected  atomic.Int64
+	)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			switch status := tryBuyItem(t, token, item); status {
+			case http.StatusOK:
+				succeeded.Add(1)
+			case http.StatusUnprocessableEntity:
+				rejected.Add(1)
+			default:
+				t.Errorf("unexpected status code: %d", status)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Все покупки сверх баланса должны быть отклонены, а списания не должны теряться.
+	coins := getUserInfo(t, token)
+	assert.GreaterOrEqual(t, coins, 0, "Balance must never go negative")
+	assert.Equal(t, int64(startBalance/itemPrice), succeeded.Load(), "Unexpected number of successful purchases")
+	assert.Equal(t, int64(requests-startBalance/itemPrice), rejected.Load(), "Unexpected number of rejected purchases")
+	assert.Equal(t, startBalance-int(succeeded.Load())*itemPrice, coins, "Balance drifted from purchase history")
+}
+
 // Регистрация пользователя
 func registerUser(t *testing.T, username, password string) (string, string) {
 	url := fmt.Sprintf("%s/api/auth/register", baseURL)
@@ -60,17 +107,28 @@ func registerUser(t *testing.T, username, password string) (string, string) {
 
 // Покупка товара
 func buyItem(t *testing.T, token, item string) {
-	url := fmt.Sprintf("%s/api/buy/%s", baseURL, item)
-	req, err := http.NewRequest("GET", url, nil)
+	assert.Equal(t, http.StatusOK, tryBuyItem(t, token, item), "Failed to buy item")
+}
+
+// Попытка покупки товара, возвращает код ответа
+func tryBuyItem(t *testing.T, token, item string) int {
+	url := fmt.Sprintf("%s/api/buy", baseURL)
+	body, err := json.Marshal(map[string]string{"item": item})
+	assert.NoError(t, err, "Failed to marshal request")
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
 	assert.NoError(t, err, "Failed to create request")
 
 	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{}
 	resp, err := client.Do(req)
-	assert.NoError(t, err, "Failed to buy item")
+	if !assert.NoError(t, err, "Failed to buy item") {
+		return 0
+	}
 	defer resp.Body.Close()
 
-	assert.Equal(t, http.StatusOK, resp.StatusCode, "Failed to buy item")
+	return resp.StatusCode
 }
 
 // Получение информации о пользователе